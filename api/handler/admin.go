@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// AddContestRuns 添加提交记录，请求体为单个提交或提交数组
+func AddContestRuns(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 解析请求体
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		errors.SendError(c, errors.NewBadRequest("读取请求体失败"))
+		return
+	}
+
+	var runs []model.Run
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '{' {
+		var run model.Run
+		err = json.Unmarshal(body, &run)
+		runs = append(runs, run)
+	} else {
+		err = json.Unmarshal(body, &runs)
+	}
+	if err != nil {
+		errors.SendError(c, errors.NewBadRequest("提交数据格式错误: "+err.Error()))
+		return
+	}
+
+	// 调用服务层写入数据
+	added, err := service.AddContestRuns(path, runs)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, added)
+}
+
+// UpdateContestRun 修改提交的评测结果
+func UpdateContestRun(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 解析请求体
+	var verdict service.RunVerdict
+	if err := c.ShouldBindJSON(&verdict); err != nil {
+		errors.SendError(c, errors.NewBadRequest("请求数据格式错误: "+err.Error()))
+		return
+	}
+
+	// 调用服务层写入数据
+	run, err := service.UpdateContestRun(path, verdict)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, run)
+}
+
+// PutContestTeams 替换比赛的队伍数据
+func PutContestTeams(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 解析请求体
+	var teamList model.TeamList
+	if err := c.ShouldBindJSON(&teamList); err != nil {
+		errors.SendError(c, errors.NewBadRequest("队伍数据格式错误: "+err.Error()))
+		return
+	}
+
+	// 调用服务层写入数据
+	teamList, err := service.PutContestTeams(path, teamList)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, teamList)
+}
+
+// PutContestConfig 替换比赛配置
+func PutContestConfig(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 解析请求体
+	var config model.ContestConfig
+	if err := c.ShouldBindJSON(&config); err != nil {
+		errors.SendError(c, errors.NewBadRequest("比赛配置格式错误: "+err.Error()))
+		return
+	}
+
+	// 调用服务层写入数据
+	saved, err := service.PutContestConfig(path, &config)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, saved)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// GetCLICS 返回 CLICS Contest API 数据
+//
+// 路径形如 /api/contests/{比赛路径或比赛 id}/{端点}，未指定端点时返回比赛信息，
+// 比赛 id 为比赛路径中的 / 替换为 - 的结果，如 icpc-2024-nanjing。
+// 为了兼容标准工具，成功时直接返回 CLICS 对象而不包装响应。
+func GetCLICS(c *gin.Context) {
+	// 拆分比赛路径和端点
+	path, endpoint := service.SplitCLICSPath(c.Param("path"))
+
+	// 获取请求参数
+	var query service.BoardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// event-feed 以 NDJSON 格式返回
+	if endpoint == service.CLICSEventFeed {
+		events, err := service.GetCLICSEventFeed(path, query)
+		if err != nil {
+			errors.SendError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	// 调用服务层获取数据
+	data, err := service.GetCLICS(path, endpoint, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	c.JSON(http.StatusOK, data)
+}
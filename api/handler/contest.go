@@ -4,7 +4,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/lllllan02/scoreboardv2/internal/service"
 	"github.com/lllllan02/scoreboardv2/pkg/errors"
-	"github.com/spf13/cast"
 )
 
 // GetContestList 返回比赛列表数据
@@ -43,11 +42,16 @@ func GetContestConfig(c *gin.Context) {
 func GetContestRank(c *gin.Context) {
 	// 获取请求路径
 	path := c.Param("path")
-	t := cast.ToInt(c.Query("t"))
-	group := c.Query("group")
+
+	// 获取请求参数
+	var query service.BoardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
 
 	// 调用服务层获取数据
-	rank, err := service.GetContestRank(path, group, t)
+	rank, err := service.GetContestRank(path, query)
 	if err != nil {
 		errors.SendError(c, err)
 		return
@@ -84,11 +88,16 @@ func GetContestRun(c *gin.Context) {
 func GetContestStat(c *gin.Context) {
 	// 获取请求路径
 	path := c.Param("path")
-	group := c.Query("group")
-	t := cast.ToInt(c.Query("t"))
+
+	// 获取请求参数
+	var query service.StatQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
 
 	// 调用服务层获取数据
-	stat, err := service.GetContestStat(path, group, t)
+	stat, err := service.GetContestStat(path, query)
 	if err != nil {
 		errors.SendError(c, err)
 		return
@@ -97,14 +106,66 @@ func GetContestStat(c *gin.Context) {
 	errors.SendSuccess(c, stat)
 }
 
+// GetProblemAnalytics 返回题目统计分析数据
+func GetProblemAnalytics(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 获取请求参数
+	var query service.StatQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 调用服务层获取数据
+	problems, err := service.GetProblemAnalytics(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, problems)
+}
+
+// GetOrgRank 返回学校排名数据
+func GetOrgRank(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 获取请求参数
+	var query service.OrgQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 调用服务层获取数据
+	orgRank, err := service.GetOrgRank(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, orgRank)
+}
+
 // GetTeamTrend 返回队伍排名趋势数据
 func GetTeamTrend(c *gin.Context) {
 	// 获取请求路径
 	path := c.Param("path")
-	teamId := c.Query("team_id")
+
+	// 获取请求参数
+	var query service.TrendQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
 
 	// 调用服务层获取数据
-	trend, err := service.GetTeamTrend(path, teamId)
+	trend, err := service.GetTeamTrend(path, query)
 	if err != nil {
 		errors.SendError(c, err)
 		return
@@ -113,3 +174,65 @@ func GetTeamTrend(c *gin.Context) {
 	// 返回数据
 	errors.SendSuccess(c, trend)
 }
+
+// GetContestResolver 返回比赛滚榜步骤
+func GetContestResolver(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 获取请求参数
+	var query service.ResolverQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 调用服务层获取数据
+	resolver, err := service.GetContestResolver(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, resolver)
+}
+
+// GetContestResolverState 返回滚榜到指定步骤时的排名数据
+func GetContestResolverState(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 获取请求参数
+	var query service.ResolverQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 调用服务层获取数据
+	state, err := service.GetContestResolverState(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, state)
+}
+
+// GetContestGroup 返回比赛组别列表
+func GetContestGroup(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 调用服务层获取数据
+	groups, err := service.GetContestGroup(path)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 返回数据
+	errors.SendSuccess(c, groups)
+}
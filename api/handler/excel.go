@@ -0,0 +1,442 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+// Excel 工作表名称
+const (
+	sheetRank    = "排名"
+	sheetProblem = "题目统计"
+	sheetRun     = "提交记录"
+	sheetContest = "比赛信息"
+)
+
+// excelStyles Excel 导出使用的单元格样式
+type excelStyles struct {
+	header      int // 表头
+	cell        int // 普通单元格
+	text        int // 左对齐的文本
+	solved      int // 通过
+	firstSolved int // 一血
+	failed      int // 未通过
+	frozen      int // 封榜后未公布结果
+	pending     int // 评测中
+	medals      map[string]int
+}
+
+// newExcelStyles 创建单元格样式
+func newExcelStyles(f *excelize.File) (*excelStyles, error) {
+	border := []excelize.Border{
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+	}
+	newStyle := func(fill string, color string, bold bool, horizontal string) (int, error) {
+		style := &excelize.Style{
+			Font:      &excelize.Font{Bold: bold, Size: 11, Family: "微软雅黑", Color: color},
+			Alignment: &excelize.Alignment{Horizontal: horizontal, Vertical: "center"},
+			Border:    border,
+		}
+		if fill != "" {
+			style.Fill = excelize.Fill{Type: "pattern", Color: []string{fill}, Pattern: 1}
+		}
+		return f.NewStyle(style)
+	}
+
+	var err error
+	styles := &excelStyles{medals: make(map[string]int)}
+	for _, item := range []struct {
+		style      *int
+		fill       string
+		color      string
+		bold       bool
+		horizontal string
+	}{
+		{&styles.header, "#D9D9D9", "#000000", true, "center"},
+		{&styles.cell, "", "#000000", false, "center"},
+		{&styles.text, "", "#000000", false, "left"},
+		{&styles.solved, "#C6EFCE", "#006100", false, "center"},
+		{&styles.firstSolved, "#00B050", "#FFFFFF", true, "center"},
+		{&styles.failed, "#FFC7CE", "#9C0006", false, "center"},
+		{&styles.frozen, "#BDD7EE", "#1F4E78", false, "center"},
+		{&styles.pending, "#FFEB9C", "#9C5700", false, "center"},
+	} {
+		if *item.style, err = newStyle(item.fill, item.color, item.bold, item.horizontal); err != nil {
+			return nil, err
+		}
+	}
+
+	// 奖牌颜色
+	for medal, fill := range map[string]string{
+		service.MedalGold:   "#FFD700",
+		service.MedalSilver: "#C0C0C0",
+		service.MedalBronze: "#CD7F32",
+	} {
+		if styles.medals[medal], err = newStyle(fill, "#000000", true, "center"); err != nil {
+			return nil, err
+		}
+	}
+
+	return styles, nil
+}
+
+// problemStyle 返回题目状态对应的样式
+func (s *excelStyles) problemStyle(problem service.Problem) int {
+	switch {
+	case problem.FirstSolved:
+		return s.firstSolved
+	case problem.Solved:
+		return s.solved
+	case problem.Frozen:
+		return s.frozen
+	case problem.Pending:
+		return s.pending
+	case problem.Submitted > 0:
+		return s.failed
+	default:
+		return s.cell
+	}
+}
+
+// verdictStyle 返回评测结果对应的样式
+func (s *excelStyles) verdictStyle(status string) int {
+	switch model.Verdict(status) {
+	case model.VerdictAccepted:
+		return s.solved
+	case model.VerdictFrozen:
+		return s.frozen
+	case model.VerdictPending:
+		return s.pending
+	default:
+		return s.failed
+	}
+}
+
+// exportExcel 导出 Excel 格式
+//
+// 包含排名、题目统计、提交记录和比赛信息四个工作表，工作表以流式写入，提交记录逐条写入
+func exportExcel(c *gin.Context, path string, query service.BoardQuery, rank *service.Rank, config *model.ContestConfig) {
+	// 获取组别
+	groups, err := service.GetContestGroup(path)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 按提交时间写入的提交记录
+	walk := func(fn func(*service.Run) error) error {
+		return service.WalkContestRuns(path, service.ContestRunQuery{
+			Group:    query.Group,
+			Time:     query.Time,
+			Unfrozen: query.Unfrozen,
+		}, fn)
+	}
+
+	// 创建新的 Excel 文件，默认工作表作为排名工作表
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName(f.GetSheetName(0), sheetRank)
+	for _, sheet := range []string{sheetProblem, sheetRun, sheetContest} {
+		if _, err := f.NewSheet(sheet); err != nil {
+			errors.SendError(c, errors.NewInternalError("创建 Excel 工作表失败", err))
+			return
+		}
+	}
+
+	styles, err := newExcelStyles(f)
+	if err != nil {
+		errors.SendError(c, errors.NewInternalError("创建 Excel 样式失败", err))
+		return
+	}
+
+	for _, write := range []func() error{
+		func() error { return writeRankSheet(f, styles, rank, config, groups) },
+		func() error { return writeProblemSheet(f, styles, rank, config) },
+		func() error { return writeRunSheet(f, styles, walk) },
+		func() error { return writeContestSheet(f, styles, query, rank, config, groups) },
+	} {
+		if err := write(); err != nil {
+			errors.SendError(c, errors.NewInternalError("写入 Excel 数据失败", err))
+			return
+		}
+	}
+
+	sendExcel(c, f, config.ContestName)
+}
+
+// sendExcel 设置响应头后将 Excel 文件直接写入响应
+//
+// 工作表数据由流式写入器保存在临时文件中；xlsx 是 zip 压缩包，
+// 压缩包仍要在所有工作表写完后于最后一步生成，生成失败时尚未写入响应，可以返回错误信息
+func sendExcel(c *gin.Context, f *excelize.File, name string) {
+	// 设置响应头
+	filename := fmt.Sprintf("%s-%s.xlsx", name, time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
+
+	if err := f.Write(c.Writer); err != nil {
+		streamError(c, errors.NewInternalError("生成 Excel 文件失败", err))
+	}
+}
+
+// writeRankSheet 写入排名工作表，题目单元格按状态着色
+func writeRankSheet(f *excelize.File, styles *excelStyles, rank *service.Rank, config *model.ContestConfig, groups []*service.Group) error {
+	headers := []string{"排名", "学校", "队伍", "组别", "解题数", "罚时"}
+	widths := []float64{8, 30, 30, 16, 8, 8}
+	for i := 0; i < config.ProblemQuantity; i++ {
+		headers = append(headers, config.ProblemLabel(i))
+		widths = append(widths, 10)
+	}
+	headers = append(headers, "奖牌", "奖项")
+	widths = append(widths, 10, 30)
+
+	sheet, err := newExcelSheet(f, styles, sheetRank, headers, widths)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rank.Rows {
+		// 学校和队伍左对齐
+		record := []any{
+			row.Place,
+			excelize.Cell{StyleID: styles.text, Value: row.Organization},
+			excelize.Cell{StyleID: styles.text, Value: row.Team},
+			groupText(row, groups),
+			row.Solved,
+			row.Penalty,
+		}
+
+		// 题目按状态着色
+		for _, problem := range row.Problems {
+			record = append(record, excelize.Cell{StyleID: styles.problemStyle(problem), Value: problemText(problem)})
+		}
+
+		// 奖牌着色
+		medal, ok := styles.medals[row.Medal]
+		if !ok {
+			medal = styles.cell
+		}
+		record = append(record, excelize.Cell{StyleID: medal, Value: medalText(row)}, awardText(row))
+
+		if err := sheet.write(styles.cell, record); err != nil {
+			return err
+		}
+	}
+
+	return sheet.Flush()
+}
+
+// writeProblemSheet 写入题目统计工作表
+func writeProblemSheet(f *excelize.File, styles *excelStyles, rank *service.Rank, config *model.ContestConfig) error {
+	headers := []string{"题目", "提交次数", "通过队伍数", "尝试次数", "错误次数", "错误率", "一血时间(分钟)", "最后通过时间(分钟)"}
+	sheet, err := newExcelSheet(f, styles, sheetProblem, headers, []float64{8, 12, 12, 12, 12, 12, 18, 20})
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < config.ProblemQuantity && i < len(rank.Submitted); i++ {
+		record := []any{
+			config.ProblemLabel(i),
+			rank.Submitted[i],
+			rank.Accepted[i],
+			rank.Attempted[i],
+			rank.Dirt[i],
+			fmt.Sprintf("%.1f%%", rank.Dirty[i]*100),
+			"",
+			"",
+		}
+		if rank.Accepted[i] > 0 {
+			record[6], record[7] = rank.FirstSolved[i], rank.LastSolved[i]
+		}
+		if err := sheet.write(styles.cell, record); err != nil {
+			return err
+		}
+	}
+
+	return sheet.Flush()
+}
+
+// writeRunSheet 按 walk 给出的顺序写入提交记录工作表，评测结果按状态着色
+func writeRunSheet(f *excelize.File, styles *excelStyles, walk func(func(*service.Run) error) error) error {
+	sheet, err := newExcelSheet(f, styles, sheetRun, runHeaders, []float64{12, 12, 10, 30, 30, 8, 12, 24, 8})
+	if err != nil {
+		return err
+	}
+
+	err = walk(func(run *service.Run) error {
+		record := runRecord(run)
+		record[7] = excelize.Cell{StyleID: styles.verdictStyle(run.Status), Value: run.Status}
+		return sheet.write(styles.cell, record)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sheet.Flush()
+}
+
+// 提交记录的表头
+var runHeaders = []string{"提交 id", "提交时间", "队伍 id", "队伍", "学校", "题目", "语言", "评测结果", "重测"}
+
+// runRecord 返回提交记录的一行数据，与 runHeaders 对应
+func runRecord(run *service.Run) []any {
+	rejudged := ""
+	if run.Rejudged {
+		rejudged = "是"
+	}
+	return []any{
+		run.Id,
+		formatDuration(run.Timestamp),
+		run.TeamId,
+		run.Team,
+		run.Organization,
+		run.ProblemId,
+		run.Language,
+		run.Status,
+		rejudged,
+	}
+}
+
+// writeContestSheet 写入比赛信息工作表
+func writeContestSheet(f *excelize.File, styles *excelStyles, query service.BoardQuery, rank *service.Rank, config *model.ContestConfig, groups []*service.Group) error {
+	labels := make([]string, 0, config.ProblemQuantity)
+	for i := 0; i < config.ProblemQuantity; i++ {
+		labels = append(labels, config.ProblemLabel(i))
+	}
+	groupNames := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupNames = append(groupNames, fmt.Sprintf("%s(%d)", group.Name, group.Count))
+	}
+
+	records := [][]any{
+		{"比赛名称", config.ContestName},
+		{"开始时间", formatUnix(config.StartTime)},
+		{"结束时间", formatUnix(config.EndTime)},
+		{"比赛时长", formatDuration(config.Duration())},
+		{"封榜时长", formatDuration(config.FrozenTime * 1000)},
+		{"罚时(秒)", config.PenaltySeconds()},
+		{"罚时计算方式", config.PenaltyCalculation()},
+		{"题目数量", config.ProblemQuantity},
+		{"题目编号", strings.Join(labels, " ")},
+		{"队伍数量", len(rank.Rows)},
+		{"组别", strings.Join(groupNames, "、")},
+		{"筛选组别", query.Group},
+		{"榜单时刻", formatDuration(query.Time)},
+		{"揭晓封榜", query.Unfrozen},
+		{"导出时间", time.Now().Format(time.DateTime)},
+	}
+
+	sheet, err := newExcelSheet(f, styles, sheetContest, []string{"项目", "内容"}, []float64{16, 60})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := sheet.write(styles.text, record); err != nil {
+			return err
+		}
+	}
+
+	return sheet.Flush()
+}
+
+// excelSheet 以流式写入的工作表，行按顺序写入，写入完成后需要调用 Flush
+type excelSheet struct {
+	*excelize.StreamWriter
+	row int // 已写入的行数
+}
+
+// newExcelSheet 创建工作表的流式写入器，设置列宽，写入表头并冻结首行
+func newExcelSheet(f *excelize.File, styles *excelStyles, name string, headers []string, widths []float64) (*excelSheet, error) {
+	sw, err := f.NewStreamWriter(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 列宽需要在写入数据前设置
+	for i, width := range widths {
+		if err := sw.SetColWidth(i+1, i+1, width); err != nil {
+			return nil, err
+		}
+	}
+	err = sw.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &excelSheet{StreamWriter: sw}
+	record := make([]any, len(headers))
+	for i, header := range headers {
+		record[i] = header
+	}
+	return sheet, sheet.write(styles.header, record)
+}
+
+// write 写入下一行数据，值为 excelize.Cell 时使用其中的样式，其余单元格使用 style
+func (s *excelSheet) write(style int, record []any) error {
+	cells := make([]any, len(record))
+	for i, value := range record {
+		if cell, ok := value.(excelize.Cell); ok {
+			cells[i] = cell
+			continue
+		}
+		cells[i] = excelize.Cell{StyleID: style, Value: value}
+	}
+
+	s.row++
+	cell, err := excelize.CoordinatesToCellName(1, s.row)
+	if err != nil {
+		return err
+	}
+	return s.SetRow(cell, cells)
+}
+
+// groupText 返回队伍所属组别的名称
+func groupText(row *service.Row, groups []*service.Group) string {
+	names := make([]string, 0, len(row.GroupPlaces))
+	for _, group := range groups {
+		if _, ok := row.GroupPlaces[group.Id]; ok {
+			names = append(names, group.Name)
+		}
+	}
+	return strings.Join(names, "、")
+}
+
+// medalText 返回正式队伍奖牌的名称
+func medalText(row *service.Row) string {
+	for _, award := range row.Awards {
+		if award.Id == row.Medal+"-medal" {
+			return award.Citation
+		}
+	}
+	return ""
+}
+
+// formatDuration 将毫秒数格式化为 时:分:秒
+func formatDuration(ms int) string {
+	seconds := max(ms, 0) / 1000
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// formatUnix 格式化 Unix 时间戳(秒)，未配置时为空
+func formatUnix(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).Format(time.DateTime)
+}
@@ -1,19 +1,21 @@
 package handler
 
 import (
-	"bytes"
+	"bufio"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/middleware"
+	"github.com/lllllan02/scoreboardv2/internal/model"
 	"github.com/lllllan02/scoreboardv2/internal/service"
 	"github.com/lllllan02/scoreboardv2/pkg/errors"
-	"github.com/spf13/cast"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -24,25 +26,66 @@ const (
 	FormatJSON  ExportFormat = "json"
 	FormatCSV   ExportFormat = "csv"
 	FormatExcel ExportFormat = "excel"
+	FormatHTML  ExportFormat = "html" // 可打印的 HTML 榜单
+	FormatPDF   ExportFormat = "pdf"  // 可打印的 PDF 榜单
+
+	// ICPC 官方成绩格式
+	FormatResults    ExportFormat = "results"    // results.tsv
+	FormatScoreboard ExportFormat = "scoreboard" // scoreboard.tsv
+	FormatAwards     ExportFormat = "awards"     // CLICS awards.json
+)
+
+// 导出的数据类型
+const (
+	ExportTeam = "team" // 队伍排名
+	ExportOrg  = "org"  // 学校排名
+	ExportRun  = "run"  // 提交记录
 )
 
 // ExportContestRank 导出比赛排名数据
+//
+// type 为 org 时导出学校排名，为 run 时导出提交记录，默认导出队伍排名。
+// 队伍排名还支持可打印的 HTML、PDF 格式，以及 ICPC 官方的 results.tsv、scoreboard.tsv 和 awards.json 格式
 func ExportContestRank(c *gin.Context) {
 	// 获取请求参数
 	path := c.Param("path")
-	t := cast.ToInt(c.Query("t"))
-	group := c.Query("group")
 	format := ExportFormat(c.Query("format"))
 
+	switch c.Query("type") {
+	case "", ExportTeam:
+	case ExportOrg:
+		exportOrgRank(c, path, format)
+		return
+	case ExportRun:
+		exportRunList(c, path, format)
+		return
+	default:
+		errors.SendError(c, errors.NewBadRequest("不支持的导出类型"))
+		return
+	}
+
+	// ICPC 官方成绩格式
+	switch format {
+	case FormatResults, FormatScoreboard, FormatAwards:
+		exportOfficial(c, path, format)
+		return
+	}
+
+	var query service.BoardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
 	// 获取排名数据
-	rank, err := service.GetContestRank(path, group, t)
+	rank, err := service.GetContestRank(path, query)
 	if err != nil {
 		errors.SendError(c, err)
 		return
 	}
 
 	// 获取比赛配置
-	config, err := service.GetContestConfig(path)
+	config, err := service.LoadContestConfig(path)
 	if err != nil {
 		errors.SendError(c, err)
 		return
@@ -53,18 +96,243 @@ func ExportContestRank(c *gin.Context) {
 	case FormatJSON:
 		exportJSON(c, rank, config.ContestName)
 	case FormatCSV:
-		exportCSV(c, rank, config.ContestName)
+		exportCSV(c, rank, config)
 	case FormatExcel:
-		exportExcel(c, rank, config.ContestName)
+		exportExcel(c, path, query, rank, config)
+	case FormatHTML:
+		exportHTML(c, path, query, rank, config)
+	case FormatPDF:
+		exportPDF(c, path, query, rank, config)
 	default:
 		errors.SendError(c, errors.NewBadRequest("不支持的导出格式"))
 	}
 }
 
+// exportOrgRank 导出学校排名数据
+func exportOrgRank(c *gin.Context, path string, format ExportFormat) {
+	var query service.OrgQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 获取学校排名数据
+	orgRank, err := service.GetOrgRank(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 获取比赛配置
+	config, err := service.LoadContestConfig(path)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+	name := config.ContestName + "-学校排名"
+
+	// 表格数据
+	headers := []string{"排名", "学校", "队伍数", "最好排名", "最好队伍", "总解题数", "解题数", "罚时", "金奖", "银奖", "铜奖"}
+	records := make([][]any, 0, len(orgRank.Rows))
+	for _, row := range orgRank.Rows {
+		records = append(records, []any{
+			row.Place, row.Organization, row.Teams, row.BestPlace, row.BestTeam,
+			row.TotalSolved, row.Solved, row.Penalty, row.Gold, row.Silver, row.Bronze,
+		})
+	}
+
+	// 根据格式导出数据
+	switch format {
+	case FormatJSON:
+		exportJSON(c, orgRank, name)
+	case FormatCSV:
+		exportTableCSV(c, name, headers, records)
+	case FormatExcel:
+		exportTableExcel(c, name, "学校排名", headers, records)
+	default:
+		errors.SendError(c, errors.NewBadRequest("不支持的导出格式"))
+	}
+}
+
+// exportRunList 导出提交记录，筛选条件与提交列表一致，不分页，按提交时间排序
+//
+// 提交记录逐条写入，不会一次性加载所有提交
+func exportRunList(c *gin.Context, path string, format ExportFormat) {
+	var query service.ContestRunQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 获取比赛配置
+	config, err := service.LoadContestConfig(path)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+	name := config.ContestName + "-提交记录"
+
+	walk := func(fn func(*service.Run) error) error {
+		return service.WalkContestRuns(path, query, fn)
+	}
+
+	// 根据格式导出数据
+	switch format {
+	case FormatJSON:
+		exportJSONArray(c, name, walk)
+	case FormatCSV:
+		w := newCSVExport(c, name)
+		err := w.Write(runHeaders)
+		if err == nil {
+			err = walk(func(run *service.Run) error { return w.writeValues(runRecord(run)) })
+		}
+		if err == nil {
+			err = w.flush()
+		}
+		if err != nil {
+			streamError(c, err)
+		}
+	case FormatExcel:
+		exportRunExcel(c, walk, name)
+	default:
+		errors.SendError(c, errors.NewBadRequest("不支持的导出格式"))
+	}
+}
+
+// exportOfficial 导出 ICPC 官方成绩
+//
+// 官方成绩包含封榜后的真实结果，需要 API 令牌
+func exportOfficial(c *gin.Context, path string, format ExportFormat) {
+	if !middleware.IsAdmin(c) {
+		errors.SendError(c, errors.NewUnauthorized("导出官方成绩需要 API 令牌"))
+		return
+	}
+
+	var query service.ResultsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	if format == FormatAwards {
+		exportAwards(c, path, query)
+	} else {
+		exportResults(c, path, query, format)
+	}
+}
+
+// exportResults 导出 ICPC 格式的 results.tsv 或 scoreboard.tsv，队伍 id 为外部 id
+//
+// results.tsv 首行为 results\t1，之后每行为：
+// 外部 id、排名、奖项、解题数、罚时、最后通过时间、组别第一名。
+// scoreboard.tsv 首行为 scoreboard\t1，之后每行为：
+// 学校、外部 id、排名、解题数、罚时，以及每道题的提交次数和通过时间(未通过为 0)
+func exportResults(c *gin.Context, path string, query service.ResultsQuery, format ExportFormat) {
+	// 获取比赛成绩
+	results, err := service.GetContestResults(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	records := [][]any{{string(format), 1}}
+	for _, row := range results.Rows {
+		rank := ""
+		if row.Rank > 0 {
+			rank = strconv.Itoa(row.Rank)
+		}
+
+		if format == FormatResults {
+			records = append(records, []any{
+				row.ExternalId, rank, row.Award, row.Solved, row.Penalty, row.LastSolved, row.GroupWinner,
+			})
+			continue
+		}
+
+		record := []any{row.Organization, row.ExternalId, rank, row.Solved, row.Penalty}
+		for _, problem := range row.Problems {
+			timestamp := 0
+			if problem.Solved {
+				timestamp = problem.Timestamp
+			}
+			record = append(record, problem.Submitted, timestamp)
+		}
+		records = append(records, record)
+	}
+
+	exportTSV(c, string(format)+".tsv", records)
+}
+
+// exportAwards 导出 CLICS 格式的 awards.json，队伍 id 为外部 id
+func exportAwards(c *gin.Context, path string, query service.ResultsQuery) {
+	// 获取奖项
+	awards, err := service.GetContestAwards(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="awards.json"`)
+
+	buffer := bufio.NewWriter(c.Writer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(awards)
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if err != nil {
+		streamError(c, errors.NewInternalError("JSON 写入失败", err))
+	}
+}
+
+// exportTSV 将表格数据逐行写入响应，字段之间以制表符分隔，字段中的制表符和换行替换为空格
+func exportTSV(c *gin.Context, filename string, records [][]any) {
+	c.Header("Content-Type", "text/tab-separated-values; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	buffer := bufio.NewWriter(c.Writer)
+	replacer := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+	for _, record := range records {
+		for i, value := range record {
+			if i > 0 {
+				buffer.WriteByte('\t')
+			}
+			replacer.WriteString(buffer, fmt.Sprint(value))
+		}
+		buffer.WriteByte('\n')
+	}
+
+	if err := buffer.Flush(); err != nil {
+		streamError(c, errors.NewInternalError("TSV 写入失败", err))
+	}
+}
+
+// exportRunExcel 将提交记录导出为 Excel 格式，评测结果按状态着色
+func exportRunExcel(c *gin.Context, walk func(func(*service.Run) error) error, name string) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName(f.GetSheetName(0), sheetRun)
+
+	styles, err := newExcelStyles(f)
+	if err != nil {
+		errors.SendError(c, errors.NewInternalError("创建 Excel 样式失败", err))
+		return
+	}
+
+	if err := writeRunSheet(f, styles, walk); err != nil {
+		streamError(c, err)
+		return
+	}
+
+	sendExcel(c, f, name)
+}
+
 // exportJSON 导出 JSON 格式
-func exportJSON(c *gin.Context, rank *service.Rank, contestName string) {
+func exportJSON(c *gin.Context, data any, contestName string) {
 	// 将数据转换为 JSON
-	jsonData, err := json.MarshalIndent(rank, "", "  ")
+	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		errors.SendError(c, errors.NewInternalError("JSON 转换失败", err))
 		return
@@ -77,20 +345,53 @@ func exportJSON(c *gin.Context, rank *service.Rank, contestName string) {
 	c.Data(http.StatusOK, "application/json", jsonData)
 }
 
+// exportJSONArray 将 walk 依次给出的数据逐条写入 JSON 数组
+func exportJSONArray[T any](c *gin.Context, name string, walk func(func(T) error) error) {
+	// 设置响应头
+	filename := fmt.Sprintf("%s-%s.json", name, time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
+
+	buffer := bufio.NewWriter(c.Writer)
+	buffer.WriteString("[")
+	count := 0
+	err := walk(func(item T) error {
+		data, err := json.MarshalIndent(item, "  ", "  ")
+		if err != nil {
+			return errors.NewInternalError("JSON 转换失败", err)
+		}
+		if count > 0 {
+			buffer.WriteString(",")
+		}
+		count++
+		buffer.WriteString("\n  ")
+		_, err = buffer.Write(data)
+		return err
+	})
+	if err == nil {
+		if count > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString("]\n")
+		err = buffer.Flush()
+	}
+	if err != nil {
+		streamError(c, err)
+	}
+}
+
 // exportCSV 导出 CSV 格式
-func exportCSV(c *gin.Context, rank *service.Rank, contestName string) {
-	buf := new(bytes.Buffer)
-	// 添加 UTF-8 BOM，以便 Excel 正确识别中文
-	buf.WriteString("\xEF\xBB\xBF")
-	writer := csv.NewWriter(buf)
+func exportCSV(c *gin.Context, rank *service.Rank, config *model.ContestConfig) {
+	writer := newCSVExport(c, config.ContestName)
 
 	// 写入 CSV 头
 	headers := []string{"排名", "学校", "队伍", "解题数", "罚时"}
-	for i := 0; i < len(rank.Rows[0].Problems); i++ {
-		headers = append(headers, string(rune('A'+i)))
+	for i := 0; i < config.ProblemQuantity; i++ {
+		headers = append(headers, config.ProblemLabel(i))
 	}
+	headers = append(headers, "奖项")
 	if err := writer.Write(headers); err != nil {
-		errors.SendError(c, errors.NewInternalError("写入 CSV 头失败", err))
+		streamError(c, errors.NewInternalError("写入 CSV 头失败", err))
 		return
 	}
 
@@ -106,167 +407,147 @@ func exportCSV(c *gin.Context, rank *service.Rank, contestName string) {
 
 		// 添加每道题的状态
 		for _, problem := range row.Problems {
-			status := ""
-			if problem.Solved {
-				status = fmt.Sprintf("+%d/%d", problem.Submitted, problem.Timestamp)
-			} else if problem.Submitted > 0 {
-				status = fmt.Sprintf("-%d", problem.Submitted)
-			}
-			record = append(record, status)
+			record = append(record, problemText(problem))
 		}
+		record = append(record, awardText(row))
 
 		if err := writer.Write(record); err != nil {
-			errors.SendError(c, errors.NewInternalError("写入 CSV 数据失败", err))
+			streamError(c, errors.NewInternalError("写入 CSV 数据失败", err))
 			return
 		}
 	}
 
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		errors.SendError(c, errors.NewInternalError("CSV 写入失败", err))
+	if err := writer.flush(); err != nil {
+		streamError(c, errors.NewInternalError("CSV 写入失败", err))
+	}
+}
+
+// awardText 返回队伍获得的奖项名称
+func awardText(row *service.Row) string {
+	citations := make([]string, 0, len(row.Awards))
+	for _, award := range row.Awards {
+		citations = append(citations, award.Citation)
+	}
+	return strings.Join(citations, "、")
+}
+
+// problemText 返回题目状态的文本
+//
+// 通过为 +提交次数/通过时间，未通过为 -错误次数，有未出结果的提交时追加 ?未出结果的次数
+func problemText(problem service.Problem) string {
+	if problem.Solved {
+		return fmt.Sprintf("+%d/%d", problem.Submitted, problem.Timestamp)
+	}
+
+	text := ""
+	if failed := problem.Submitted - problem.PendingCount; failed > 0 {
+		text = fmt.Sprintf("-%d", failed)
+	}
+	if problem.PendingCount > 0 {
+		text += fmt.Sprintf("?%d", problem.PendingCount)
+	}
+	return text
+}
+
+// exportTableCSV 将表格数据导出为 CSV 格式
+func exportTableCSV(c *gin.Context, name string, headers []string, records [][]any) {
+	writer := newCSVExport(c, name)
+	if err := writer.Write(headers); err != nil {
+		streamError(c, errors.NewInternalError("写入 CSV 头失败", err))
 		return
 	}
+	for _, record := range records {
+		if err := writer.writeValues(record); err != nil {
+			streamError(c, errors.NewInternalError("写入 CSV 数据失败", err))
+			return
+		}
+	}
 
-	// 设置响应头
-	filename := fmt.Sprintf("%s-%s.csv", contestName, time.Now().Format("20060102150405"))
+	if err := writer.flush(); err != nil {
+		streamError(c, errors.NewInternalError("CSV 写入失败", err))
+	}
+}
+
+// csvExport 直接写入响应的 CSV 导出
+//
+// 数据先写入缓冲区，缓冲区写满时写入响应，因此在写入第一批数据前出错仍可以返回错误信息
+type csvExport struct {
+	*csv.Writer
+	buffer *bufio.Writer
+}
+
+// newCSVExport 设置响应头并写入 UTF-8 BOM
+func newCSVExport(c *gin.Context, name string) *csvExport {
+	filename := fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102150405"))
 	c.Header("Content-Type", "text/csv; charset=utf-8")
 	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
-	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+
+	buffer := bufio.NewWriter(c.Writer)
+	// 添加 UTF-8 BOM，以便 Excel 正确识别中文
+	buffer.WriteString("\xEF\xBB\xBF")
+	return &csvExport{Writer: csv.NewWriter(buffer), buffer: buffer}
 }
 
-// exportExcel 导出 Excel 格式
-func exportExcel(c *gin.Context, rank *service.Rank, contestName string) {
-	// 创建新的 Excel 文件
-	f := excelize.NewFile()
-	defer f.Close()
+// writeValues 写入一行数据，值按默认格式转换为文本
+func (w *csvExport) writeValues(record []any) error {
+	line := make([]string, len(record))
+	for i, value := range record {
+		line[i] = fmt.Sprint(value)
+	}
+	return w.Write(line)
+}
 
-	// 创建一个工作表
-	sheetName := "排名"
-	index, err := f.NewSheet(sheetName)
-	if err != nil {
-		errors.SendError(c, errors.NewInternalError("创建 Excel 工作表失败", err))
-		return
+// flush 将缓冲区中剩余的数据写入响应
+func (w *csvExport) flush() error {
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
 	}
-	f.SetActiveSheet(index)
-
-	// 设置表头样式
-	headerStyle, err := f.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Bold:   true,
-			Size:   12,
-			Family: "微软雅黑",
-		},
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Color:   []string{"#C6EFCE"},
-			Pattern: 1,
-		},
-		Alignment: &excelize.Alignment{
-			Horizontal: "center",
-			Vertical:   "center",
-		},
-		Border: []excelize.Border{
-			{Type: "left", Color: "#000000", Style: 1},
-			{Type: "top", Color: "#000000", Style: 1},
-			{Type: "right", Color: "#000000", Style: 1},
-			{Type: "bottom", Color: "#000000", Style: 1},
-		},
-	})
-	if err != nil {
-		errors.SendError(c, errors.NewInternalError("创建 Excel 样式失败", err))
+	return w.buffer.Flush()
+}
+
+// streamError 处理导出过程中的错误
+//
+// 尚未写入响应时返回错误信息，否则只能记录错误并中断响应，客户端会收到不完整的文件
+func streamError(c *gin.Context, err error) {
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		errors.SendError(c, err)
 		return
 	}
+	c.Error(err)
+	c.Abort()
+}
 
-	// 设置单元格样式
-	cellStyle, err := f.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Size:   11,
-			Family: "微软雅黑",
-		},
-		Alignment: &excelize.Alignment{
-			Horizontal: "center",
-			Vertical:   "center",
-		},
-		Border: []excelize.Border{
-			{Type: "left", Color: "#000000", Style: 1},
-			{Type: "top", Color: "#000000", Style: 1},
-			{Type: "right", Color: "#000000", Style: 1},
-			{Type: "bottom", Color: "#000000", Style: 1},
-		},
-	})
+// exportTableExcel 将表格数据导出为 Excel 格式，表头加粗并冻结首行
+func exportTableExcel(c *gin.Context, name string, sheetName string, headers []string, records [][]any) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName(f.GetSheetName(0), sheetName)
+
+	styles, err := newExcelStyles(f)
 	if err != nil {
 		errors.SendError(c, errors.NewInternalError("创建 Excel 样式失败", err))
 		return
 	}
 
-	// 写入表头
-	headers := []string{"排名", "学校", "队伍", "解题数", "罚时"}
-	for i := 0; i < len(rank.Rows[0].Problems); i++ {
-		headers = append(headers, string(rune('A'+i)))
+	// 写入表头和数据行
+	widths := make([]float64, len(headers))
+	for i := range widths {
+		widths[i] = 12
 	}
-	for i, header := range headers {
-		cell := fmt.Sprintf("%c%d", rune('A'+i), 1)
-		f.SetCellValue(sheetName, cell, header)
-		f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	sheet, err := newExcelSheet(f, styles, sheetName, headers, widths)
+	for i := 0; err == nil && i < len(records); i++ {
+		err = sheet.write(styles.cell, records[i])
 	}
-
-	// 设置列宽
-	f.SetColWidth(sheetName, "A", "A", 8)  // 排名
-	f.SetColWidth(sheetName, "B", "B", 30) // 学校
-	f.SetColWidth(sheetName, "C", "C", 30) // 队伍
-	f.SetColWidth(sheetName, "D", "D", 10) // 解题数
-	f.SetColWidth(sheetName, "E", "E", 10) // 罚时
-	// 设置题目列的宽度
-	lastCol := rune('E' + len(rank.Rows[0].Problems))
-	f.SetColWidth(sheetName, "F", string(lastCol), 12)
-
-	// 写入数据行
-	for i, row := range rank.Rows {
-		rowNum := i + 2 // 从第2行开始写入数据
-		// 基本信息
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", rowNum), row.Place)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", rowNum), row.Organization)
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", rowNum), row.Team)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", rowNum), row.Solved)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", rowNum), row.Penalty)
-
-		// 题目状态
-		for j, problem := range row.Problems {
-			cell := fmt.Sprintf("%c%d", rune('F'+j), rowNum)
-			if problem.Solved {
-				f.SetCellValue(sheetName, cell, fmt.Sprintf("+%d/%d", problem.Submitted, problem.Timestamp))
-			} else if problem.Submitted > 0 {
-				f.SetCellValue(sheetName, cell, fmt.Sprintf("-%d", problem.Submitted))
-			}
-		}
-
-		// 设置整行样式
-		for j := 0; j < len(headers); j++ {
-			cell := fmt.Sprintf("%c%d", rune('A'+j), rowNum)
-			f.SetCellStyle(sheetName, cell, cell, cellStyle)
-		}
+	if err == nil {
+		err = sheet.Flush()
 	}
-
-	// 冻结首行
-	f.SetPanes(sheetName, &excelize.Panes{
-		Freeze:      true,
-		Split:       false,
-		XSplit:      0,
-		YSplit:      1,
-		TopLeftCell: "A2",
-		ActivePane:  "bottomLeft",
-	})
-
-	// 将文件写入缓冲区
-	buffer, err := f.WriteToBuffer()
 	if err != nil {
-		errors.SendError(c, errors.NewInternalError("生成 Excel 文件失败", err))
+		errors.SendError(c, errors.NewInternalError("写入 Excel 数据失败", err))
 		return
 	}
 
-	// 设置响应头
-	filename := fmt.Sprintf("%s-%s.xlsx", contestName, time.Now().Format("20060102150405"))
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
-	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buffer.Bytes())
+	sendExcel(c, f, name)
 }
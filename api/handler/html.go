@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+var (
+	//go:embed template/standings.html
+	standingsHTML string
+
+	// 打印榜单的 HTML 模板
+	standingsTemplate = template.Must(template.New("standings").Funcs(template.FuncMap{
+		"inc":         func(i int) int { return i + 1 },
+		"medalColor":  func(medal string) string { return medalColors[medal] },
+		"statusFill":  func(status string) string { return statusColors[status][0] },
+		"statusColor": func(status string) string { return statusColors[status][1] },
+	}).Parse(standingsHTML))
+)
+
+// standingsView HTML 模板使用的榜单，图片转换为 data URI
+type standingsView struct {
+	*standings
+	Pages  [][]*standingsRow // 分页的队伍，没有队伍时为一个空页，与 PDF 一致显示标题
+	Logo   template.URL
+	Banner template.URL
+	Legend []standingsCell
+}
+
+// exportHTML 导出可打印的 HTML 榜单，分页打印，图片内嵌在文件中
+func exportHTML(c *gin.Context, path string, query service.BoardQuery, rank *service.Rank, config *model.ContestConfig) {
+	s, err := newStandings(path, query, rank, config)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	view := &standingsView{
+		standings: s,
+		Pages:     s.Pages,
+		Logo:      dataURI(s.Logo),
+		Banner:    dataURI(s.Banner),
+		Legend:    standingsLegend,
+	}
+	if len(view.Pages) == 0 {
+		view.Pages = [][]*standingsRow{nil}
+	}
+	buf := new(bytes.Buffer)
+	if err := standingsTemplate.Execute(buf, view); err != nil {
+		errors.SendError(c, errors.NewInternalError("生成 HTML 失败", err))
+		return
+	}
+
+	// 设置响应头
+	filename := fmt.Sprintf("%s-%s.html", config.ContestName, time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// dataURI 将图片转换为 data URI，没有图片时为空
+func dataURI(image []byte) template.URL {
+	if len(image) == 0 {
+		return ""
+	}
+	return template.URL("data:" + http.DetectContentType(image) + ";base64," + base64.StdEncoding.EncodeToString(image))
+}
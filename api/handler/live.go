@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/config"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// GetContestLive 通过 SSE 推送比赛排行榜的实时变化
+//
+// 连接建立后先推送 rank 事件(完整排行榜)，之后每当数据变化时推送 diff 事件(排行榜的变化)，
+// 并定期推送 ping 事件保持连接
+func GetContestLive(c *gin.Context) {
+	// 获取请求路径
+	path := c.Param("path")
+
+	// 获取请求参数
+	var query service.BoardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 获取当前排行榜
+	rank, err := service.GetLiveRank(path, query)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	// 订阅比赛数据的变化
+	changes, cancel := service.SubscribeContest(path)
+	defer cancel()
+
+	heartbeat := config.GetConfig().Live.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("rank", rank)
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			c.SSEvent("ping", time.Now().Unix())
+		case <-changes:
+			next, err := service.GetLiveRank(path, query)
+			if err != nil {
+				c.SSEvent("error", err.Error())
+				return true
+			}
+
+			diff := service.DiffRank(rank, next)
+			rank = next
+			if !diff.IsEmpty() {
+				c.SSEvent("diff", diff)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/lllllan02/scoreboardv2/pkg/pdf"
+)
+
+// PDF 榜单的版式(点)
+const (
+	pdfMargin       = 28.0 // 页边距
+	pdfBannerHeight = 60.0 // 横幅的最大高度
+	pdfLogoSize     = 36.0 // logo 的高度
+	pdfHeaderHeight = 44.0 // 标题区域的高度
+	pdfRowHeight    = 15.0 // 表格的行高
+	pdfFontSize     = 8.0  // 表格的字号
+)
+
+var (
+	pdfBlack = color.Gray{Y: 0x00}
+	pdfGray  = color.Gray{Y: 0x55}
+)
+
+// pdfColumn PDF 表格的一列
+type pdfColumn struct {
+	x     float64
+	width float64
+}
+
+// exportPDF 导出可打印的 PDF 榜单，A4 横向分页，每页重复标题和表头
+//
+// 中文使用阅读器内置的宋体，不嵌入字体文件
+func exportPDF(c *gin.Context, path string, query service.BoardQuery, rank *service.Rank, config *model.ContestConfig) {
+	s, err := newStandings(path, query, rank, config)
+	if err != nil {
+		errors.SendError(c, err)
+		return
+	}
+
+	doc := pdf.New(pdf.A4Height, pdf.A4Width)
+	logo, banner := pdfImage(doc, s.Logo), pdfImage(doc, s.Banner)
+	columns, problemFontSize := pdfColumns(doc.Width()-2*pdfMargin, len(s.Problems))
+
+	headers := append([]string{"排名", "队伍", "学校", "解题", "罚时"}, s.Problems...)
+	pages := s.Pages
+	if len(pages) == 0 {
+		pages = [][]*standingsRow{nil}
+	}
+	for index, rows := range pages {
+		page := doc.AddPage()
+		y := pdfMargin
+
+		// 横幅只在第一页显示
+		if index == 0 && banner != nil {
+			width, height := fitImage(banner, doc.Width()-2*pdfMargin, pdfBannerHeight)
+			page.Image(banner, (doc.Width()-width)/2, y, width, height)
+			y += height + 6
+		}
+
+		// 标题、说明和页码
+		x := pdfMargin
+		if logo != nil {
+			width, height := fitImage(logo, pdfLogoSize*4, pdfLogoSize)
+			page.Image(logo, x, y, width, height)
+			x += width + 8
+		}
+		page.Text(x, y+16, 16, pdfBlack, s.Title)
+		page.Text(x, y+32, 9, pdfGray, s.Subtitle)
+		number := fmt.Sprintf("第 %d/%d 页", index+1, len(pages))
+		page.Text(doc.Width()-pdfMargin-pdf.TextWidth(number, 9), y+16, 9, pdfGray, number)
+		y += pdfHeaderHeight
+
+		if len(rows) == 0 {
+			page.Text(pdfMargin, y+16, 12, pdfBlack, "暂无队伍")
+			continue
+		}
+
+		// 表头
+		for i, header := range headers {
+			drawPDFCell(page, columns[i], y, header, pdfFontSize, hexColor("#D9D9D9"), pdfBlack, true)
+		}
+		y += pdfRowHeight
+
+		// 队伍
+		for _, row := range rows {
+			drawPDFCell(page, columns[0], y, strconv.Itoa(row.Place), pdfFontSize, hexColor(medalColors[row.Medal]), pdfBlack, true)
+			drawPDFTeam(page, columns[1], y, row)
+			drawPDFCell(page, columns[2], y, row.Organization, pdfFontSize, nil, pdfBlack, false)
+			drawPDFCell(page, columns[3], y, strconv.Itoa(row.Solved), pdfFontSize, nil, pdfBlack, true)
+			drawPDFCell(page, columns[4], y, strconv.Itoa(row.Penalty), pdfFontSize, nil, pdfBlack, true)
+			for i, problem := range row.Problems {
+				fill, textColor := pdfStatusColors(problem.Status)
+				drawPDFCell(page, columns[5+i], y, problem.Text, problemFontSize, fill, textColor, true)
+			}
+			y += pdfRowHeight
+		}
+
+		// 最后一页显示图例和导出时间
+		if index == len(pages)-1 {
+			x, y := pdfMargin, y+6
+			for _, legend := range standingsLegend {
+				fill, textColor := pdfStatusColors(legend.Status)
+				width := pdf.TextWidth(legend.Text, pdfFontSize) + 8
+				drawPDFCell(page, pdfColumn{x: x, width: width}, y, legend.Text, pdfFontSize, fill, textColor, true)
+				x += width + 6
+			}
+			page.Text(x, y+pdfRowHeight/2+pdfFontSize*0.35, pdfFontSize, pdfGray, "导出时间 "+s.Exported)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTo(buf); err != nil {
+		errors.SendError(c, errors.NewInternalError("生成 PDF 失败", err))
+		return
+	}
+
+	// 设置响应头
+	filename := fmt.Sprintf("%s-%s.pdf", config.ContestName, time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename*=UTF-8''%s`, url.PathEscape(filename)))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// pdfColumns 计算表格各列的位置和题目列的字号，题目较多时缩小题目列
+func pdfColumns(width float64, problems int) ([]pdfColumn, float64) {
+	widths := []float64{30, 0, 0, 30, 40}
+	fixed := widths[0] + widths[3] + widths[4]
+
+	problemWidth := 36.0
+	if problems > 0 {
+		problemWidth = min(problemWidth, (width-fixed)*0.6/float64(problems))
+	}
+	rest := width - fixed - problemWidth*float64(problems)
+	widths[1], widths[2] = rest*0.58, rest*0.42
+	for i := 0; i < problems; i++ {
+		widths = append(widths, problemWidth)
+	}
+
+	columns := make([]pdfColumn, len(widths))
+	x := pdfMargin
+	for i, w := range widths {
+		columns[i] = pdfColumn{x: x, width: w}
+		x += w
+	}
+
+	// 题目状态最长为 +99/300 的形式
+	return columns, min(pdfFontSize, (problemWidth-2)/4)
+}
+
+// drawPDFCell 绘制带边框的单元格
+func drawPDFCell(page *pdf.Page, column pdfColumn, y float64, text string, size float64, fill, textColor color.Color, center bool) {
+	page.Rect(column.x, y, column.width, pdfRowHeight, fill, pdfBlack)
+	drawPDFText(page, column, y, text, size, textColor, center)
+}
+
+// drawPDFText 在单元格中绘制垂直居中的文本，超出宽度时截断
+func drawPDFText(page *pdf.Page, column pdfColumn, y float64, text string, size float64, textColor color.Color, center bool) {
+	text = pdf.Truncate(text, size, column.width-4)
+	x := column.x + 2
+	if center {
+		x = column.x + (column.width-pdf.TextWidth(text, size))/2
+	}
+	page.Text(x, y+pdfRowHeight/2+size*0.35, size, textColor, text)
+}
+
+// drawPDFTeam 绘制队伍名称，组别标记以小号字显示在名称之后
+func drawPDFTeam(page *pdf.Page, column pdfColumn, y float64, row *standingsRow) {
+	markers := ""
+	if len(row.Markers) > 0 {
+		markers = "[" + strings.Join(row.Markers, "][") + "]"
+	}
+	markerSize := pdfFontSize * 0.75
+	markerWidth := min(pdf.TextWidth(markers, markerSize), column.width/2)
+
+	page.Rect(column.x, y, column.width, pdfRowHeight, nil, pdfBlack)
+	drawPDFText(page, pdfColumn{x: column.x, width: column.width - markerWidth}, y, row.Team, pdfFontSize, pdfBlack, false)
+	if markers != "" {
+		markers = pdf.Truncate(markers, markerSize, markerWidth)
+		x := column.x + column.width - markerWidth - 2
+		page.Text(x, y+pdfRowHeight/2+markerSize*0.35, markerSize, pdfGray, markers)
+	}
+}
+
+// pdfImage 将图片添加到文档，没有图片或图片无法解码时返回空
+func pdfImage(doc *pdf.Document, data []byte) *pdf.Image {
+	if len(data) == 0 {
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return doc.AddImage(img)
+}
+
+// fitImage 返回按比例缩放后不超过 maxWidth × maxHeight 的图片大小
+func fitImage(img *pdf.Image, maxWidth, maxHeight float64) (width, height float64) {
+	w, h := img.Size()
+	scale := min(maxWidth/float64(w), maxHeight/float64(h))
+	return float64(w) * scale, float64(h) * scale
+}
+
+// pdfStatusColors 返回题目状态的背景色和文字颜色，没有提交时不填充背景
+func pdfStatusColors(status string) (fill, text color.Color) {
+	colors, ok := statusColors[status]
+	if !ok {
+		return nil, pdfBlack
+	}
+	return hexColor(colors[0]), hexColor(colors[1])
+}
+
+// hexColor 将 #RRGGBB 格式的颜色转换为 color.Color，格式不正确时返回空
+func hexColor(hex string) color.Color {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}
+}
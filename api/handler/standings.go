@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/internal/service"
+)
+
+// 打印榜单每页的队伍数量
+const standingsPageRows = 25
+
+// 题目状态
+const (
+	statusFirstSolved = "first-solved" // 一血
+	statusSolved      = "solved"       // 通过
+	statusFailed      = "failed"       // 未通过
+	statusFrozen      = "frozen"       // 封榜后未公布结果
+	statusPending     = "pending"      // 评测中
+)
+
+// 题目状态的背景色和文字颜色，与 Excel 导出一致
+var statusColors = map[string][2]string{
+	statusFirstSolved: {"#00B050", "#FFFFFF"},
+	statusSolved:      {"#C6EFCE", "#006100"},
+	statusFailed:      {"#FFC7CE", "#9C0006"},
+	statusFrozen:      {"#BDD7EE", "#1F4E78"},
+	statusPending:     {"#FFEB9C", "#9C5700"},
+}
+
+// 奖牌的背景色
+var medalColors = map[string]string{
+	service.MedalGold:   "#FFD700",
+	service.MedalSilver: "#C0C0C0",
+	service.MedalBronze: "#CD7F32",
+}
+
+// 题目状态的图例
+var standingsLegend = []standingsCell{
+	{Text: "一血", Status: statusFirstSolved},
+	{Text: "通过", Status: statusSolved},
+	{Text: "未通过", Status: statusFailed},
+	{Text: "封榜", Status: statusFrozen},
+	{Text: "评测中", Status: statusPending},
+}
+
+// standings 打印用的榜单
+type standings struct {
+	Title    string            // 比赛名称
+	Subtitle string            // 榜单的组别、时刻和封榜状态
+	Exported string            // 导出时间
+	Logo     []byte            // logo 图片，没有时为空
+	Banner   []byte            // 横幅图片，没有时为空
+	Problems []string          // 题目编号
+	Pages    [][]*standingsRow // 分页后的队伍
+}
+
+// standingsRow 打印榜单中的一支队伍
+type standingsRow struct {
+	Place        int
+	Team         string
+	Organization string
+	Markers      []string // 组别标记，不包括正式队伍
+	Solved       int
+	Penalty      int
+	Problems     []standingsCell
+	Medal        string // 奖牌：gold/silver/bronze
+	Awards       string // 获得的奖项
+}
+
+// standingsCell 打印榜单中的一道题目
+type standingsCell struct {
+	Text   string // 题目状态的文本
+	Status string // 题目状态，没有提交时为空
+}
+
+// newStandings 生成打印用的榜单，图片读取失败时不显示图片
+func newStandings(path string, query service.BoardQuery, rank *service.Rank, config *model.ContestConfig) (*standings, error) {
+	groups, err := service.GetContestGroup(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &standings{
+		Title:    config.ContestName,
+		Subtitle: standingsSubtitle(query, config, groups),
+		Exported: time.Now().Format(time.DateTime),
+		Pages:    make([][]*standingsRow, 0),
+	}
+	s.Logo, _ = service.GetContestLogo(path)
+	s.Banner, _ = service.GetContestBanner(path)
+	for i := 0; i < config.ProblemQuantity; i++ {
+		s.Problems = append(s.Problems, config.ProblemLabel(i))
+	}
+
+	for i, row := range rank.Rows {
+		if i%standingsPageRows == 0 {
+			s.Pages = append(s.Pages, make([]*standingsRow, 0, standingsPageRows))
+		}
+
+		r := &standingsRow{
+			Place:        row.Place,
+			Team:         row.Team,
+			Organization: row.Organization,
+			Markers:      groupMarkers(row, groups),
+			Solved:       row.Solved,
+			Penalty:      row.Penalty,
+			Medal:        row.Medal,
+			Awards:       awardText(row),
+		}
+		for _, problem := range row.Problems {
+			r.Problems = append(r.Problems, standingsCell{Text: problemText(problem), Status: problemStatus(problem)})
+		}
+
+		last := len(s.Pages) - 1
+		s.Pages[last] = append(s.Pages[last], r)
+	}
+
+	return s, nil
+}
+
+// standingsSubtitle 返回榜单的组别、时刻和封榜状态
+func standingsSubtitle(query service.BoardQuery, config *model.ContestConfig, groups []*service.Group) string {
+	subtitle := ""
+	for _, g := range groups {
+		if g.Id == query.Group || (query.Group == "" && g.Id == model.GroupAll) {
+			subtitle = g.Name + " · "
+		}
+	}
+	subtitle += "比赛时间 " + formatDuration(query.Time)
+
+	switch {
+	case query.Unfrozen:
+		subtitle += " · 揭晓封榜"
+	case config.FrozenTime > 0:
+		subtitle += " · 封榜"
+	}
+	return subtitle
+}
+
+// groupMarkers 返回队伍所属组别的名称，不包括所有队伍和正式队伍
+func groupMarkers(row *service.Row, groups []*service.Group) []string {
+	markers := make([]string, 0)
+	for _, group := range groups {
+		if group.Id == model.GroupAll || group.Id == model.GroupOfficial {
+			continue
+		}
+		if _, ok := row.GroupPlaces[group.Id]; ok {
+			markers = append(markers, group.Name)
+		}
+	}
+	return markers
+}
+
+// problemStatus 返回题目状态，与 Excel 导出的着色一致
+func problemStatus(problem service.Problem) string {
+	switch {
+	case problem.FirstSolved:
+		return statusFirstSolved
+	case problem.Solved:
+		return statusSolved
+	case problem.Frozen:
+		return statusFrozen
+	case problem.Pending:
+		return statusPending
+	case problem.Submitted > 0:
+		return statusFailed
+	default:
+		return ""
+	}
+}
@@ -21,6 +21,9 @@ func SetupRouter() *gin.Engine {
 	// 添加自定义日志中间件
 	r.Use(middleware.Logger())
 
+	// 只有管理员可以查看封榜后的真实结果
+	r.Use(middleware.FrozenGuard())
+
 	// 设置受信任的代理
 	// 对于开发环境，可以使用 127.0.0.1/8
 	// 生产环境应该指定您的负载均衡器/代理的 IP 或 CIDR 范围
@@ -34,16 +37,41 @@ func SetupRouter() *gin.Engine {
 	r.GET("/api/contests", handler.GetContestList)
 	// 获取比赛配置
 	r.GET("/api/config/*path", handler.GetContestConfig)
+	// 获取比赛组别
+	r.GET("/api/group/*path", handler.GetContestGroup)
 	// 获取比赛排名
 	r.GET("/api/rank/*path", handler.GetContestRank)
+	// 获取学校排名
+	r.GET("/api/org-rank/*path", handler.GetOrgRank)
 	// 获取比赛提交
 	r.GET("/api/run/*path", handler.GetContestRun)
 	// 获取比赛统计
 	r.GET("/api/stat/*path", handler.GetContestStat)
+	// 获取题目统计分析
+	r.GET("/api/problem-stat/*path", handler.GetProblemAnalytics)
 	// 队伍排名趋势
 	r.GET("/api/team-trend/*path", handler.GetTeamTrend)
+	// 实时排名变化(SSE)
+	r.GET("/api/live/*path", handler.GetContestLive)
+	// 比赛滚榜步骤，包含封榜后的真实结果，需要 API 令牌
+	r.GET("/api/resolver/*path", middleware.AdminAuth(), handler.GetContestResolver)
+	// 滚榜指定步骤的排名，需要 API 令牌
+	r.GET("/api/resolver-rank/*path", middleware.AdminAuth(), handler.GetContestResolverState)
 	// 导出比赛排名
 	r.GET("/api/export/*path", handler.ExportContestRank)
+	// 管理接口，需要 API 令牌
+	admin := r.Group("/api/admin", middleware.AdminAuth())
+	// 添加提交
+	admin.POST("/run/*path", handler.AddContestRuns)
+	// 修改提交的评测结果
+	admin.PATCH("/run/*path", handler.UpdateContestRun)
+	// 替换队伍数据
+	admin.PUT("/team/*path", handler.PutContestTeams)
+	// 替换比赛配置
+	admin.PUT("/config/*path", handler.PutContestConfig)
+
+	// CLICS Contest API
+	r.GET("/api/contests/*path", handler.GetCLICS)
 
 	return r
 }
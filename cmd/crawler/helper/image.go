@@ -1,7 +1,6 @@
 package helper
 
 import (
-	_ "embed"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -11,16 +10,11 @@ import (
 	"strings"
 
 	"github.com/lllllan02/scoreboardv2/config"
+	"github.com/lllllan02/scoreboardv2/internal/assets"
 	"github.com/lllllan02/scoreboardv2/internal/model"
 )
 
 var (
-	//go:embed ccpc.png
-	ccpcLogo []byte // 嵌入 CCPC 比赛的默认 logo
-
-	//go:embed icpc.png
-	icpcLogo []byte // 嵌入 ICPC 比赛的默认 logo
-
 	// 下载 logo 时显示的进度条
 	logobar *ProgressBar
 
@@ -88,10 +82,8 @@ func FetchLogo(contest *model.Contest) {
 	switch present := strings.ToLower(contest.Config.Logo.Preset); present {
 	case "":
 		// 不做任何处理
-	case "ccpc":
-		saveImage(path, ccpcLogo) // 使用 CCPC logo
-	case "icpc":
-		saveImage(path, icpcLogo) // 使用 ICPC logo
+	case "ccpc", "icpc":
+		saveImage(path, assets.PresetLogo(present)) // 使用内置的 CCPC、ICPC logo
 	default:
 		// 从远程 URL 获取 logo
 		url := fmt.Sprintf("https://board.xcpcio.com/logos/%s.png", present)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/config"
+	"github.com/lllllan02/scoreboardv2/internal/importer"
+)
+
+var (
+	// 数据保存路径
+	path = config.GetConfig().Data.Path
+)
+
+func main() {
+	format := flag.String("format", "clics", "数据源格式: "+strings.Join(importer.Formats(), "、"))
+	input := flag.String("input", "", "数据源文件或目录路径")
+	output := flag.String("output", "", "比赛路径，如 /icpc/2024/nanjing，数据保存在数据目录下")
+	name := flag.String("name", "", "比赛名称，覆盖数据源中的名称")
+	start := flag.String("start", "", "比赛开始时间，如 2024-11-03 09:00:00，覆盖数据源中的时间")
+	duration := flag.Duration("duration", 0, "比赛时长，如 5h，覆盖数据源中的时长")
+	flag.Parse()
+
+	if *input == "" || *output == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	imp, err := importer.New(*format)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	options := importer.Options{Name: *name, Duration: *duration}
+	if *start != "" {
+		if options.StartTime, err = time.ParseInLocation(time.DateTime, *start, time.Local); err != nil {
+			fmt.Printf("无法解析开始时间 %s: %v\n", *start, err)
+			os.Exit(2)
+		}
+	}
+
+	contest, err := imp.Import(*input)
+	if err != nil {
+		fmt.Printf("导入 %s 失败: %v\n", *input, err)
+		os.Exit(1)
+	}
+	contest.Apply(options)
+
+	dir := filepath.Join(path, *output)
+	if err := contest.Save(dir); err != nil {
+		fmt.Printf("保存比赛数据失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("成功导入 %s：%d 支队伍，%d 条提交，保存到 %s\n", contest.Config.ContestName, len(contest.Team), len(contest.Run), dir)
+}
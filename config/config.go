@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -13,6 +14,9 @@ import (
 type Config struct {
 	Server ServerConfig `mapstructure:"server" yaml:"server"`
 	Data   DataConfig   `mapstructure:"data" yaml:"data"`
+	Cache  CacheConfig  `mapstructure:"cache" yaml:"cache"`
+	Live   LiveConfig   `mapstructure:"live" yaml:"live"`
+	Admin  AdminConfig  `mapstructure:"admin" yaml:"admin"`
 }
 
 // ServerConfig 服务器配置
@@ -26,6 +30,22 @@ type DataConfig struct {
 	Path string `mapstructure:"path" yaml:"path"` // JSON 文件存储路径
 }
 
+// CacheConfig 比赛数据缓存配置
+type CacheConfig struct {
+	Capacity int `mapstructure:"capacity" yaml:"capacity"` // 最大缓存条目数，小于等于 0 表示不限制
+}
+
+// LiveConfig 实时模式配置
+type LiveConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval" yaml:"poll_interval"` // 检查比赛文件变化的间隔
+	Heartbeat    time.Duration `mapstructure:"heartbeat" yaml:"heartbeat"`         // 推送心跳的间隔，避免连接被代理断开
+}
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	Tokens []string `mapstructure:"tokens" yaml:"tokens"` // 允许写入数据的 API 令牌，为空时禁用管理接口
+}
+
 // 全局配置实例和同步控制
 var (
 	//go:embed config.example.yaml
@@ -0,0 +1,26 @@
+package assets
+
+import (
+	_ "embed"
+	"strings"
+)
+
+var (
+	//go:embed ccpc.png
+	ccpcLogo []byte // CCPC 比赛的默认 logo
+
+	//go:embed icpc.png
+	icpcLogo []byte // ICPC 比赛的默认 logo
+)
+
+// PresetLogo 返回预设类型(ccpc、icpc，不区分大小写)对应的内置 logo，没有内置 logo 时返回空
+func PresetLogo(preset string) []byte {
+	switch strings.ToLower(preset) {
+	case "ccpc":
+		return ccpcLogo
+	case "icpc":
+		return icpcLogo
+	default:
+		return nil
+	}
+}
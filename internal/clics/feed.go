@@ -0,0 +1,248 @@
+package clics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Feed event-feed 中所有事件应用后的最终数据
+type Feed struct {
+	Contest        *Contest
+	State          *State
+	JudgementTypes map[string]*JudgementType
+	Languages      map[string]*Language
+	Problems       map[string]*Problem
+	Groups         map[string]*Group
+	Organizations  map[string]*Organization
+	Teams          map[string]*Team
+	Submissions    map[string]*Submission
+	Judgements     map[string]*Judgement
+}
+
+// ReadFeed 读取 NDJSON 格式的 event-feed，按顺序应用所有事件
+func ReadFeed(r io.Reader) (*Feed, error) {
+	// 按类型保存对象的原始数据：type -> id -> data
+	objects := make(map[string]map[string]json.RawMessage)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		// 跳过心跳产生的空行
+		if len(data) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("第 %d 行解析失败: %w", line, err)
+		}
+		if err := applyEvent(objects, &event); err != nil {
+			return nil, fmt.Errorf("第 %d 行解析失败: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newFeed(objects)
+}
+
+// ReadDir 读取按端点导出的目录，如 DOMjudge 导出的 contest.json、teams.json、submissions.json 等
+//
+// 每个文件保存对应端点的响应：比赛和状态为单个对象，其余为对象数组，缺少的文件会被忽略
+func ReadDir(dir string) (*Feed, error) {
+	objects := make(map[string]map[string]json.RawMessage)
+
+	types := []string{
+		TypeContest, TypeState, TypeJudgementTypes, TypeLanguages, TypeProblems,
+		TypeGroups, TypeOrganizations, TypeTeams, TypeSubmissions, TypeJudgements,
+	}
+	for _, typ := range types {
+		data, err := os.ReadFile(filepath.Join(dir, typ+".json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := applyEvent(objects, &Event{Type: typ, Data: data}); err != nil {
+			return nil, fmt.Errorf("%s.json 解析失败: %w", typ, err)
+		}
+	}
+
+	return newFeed(objects)
+}
+
+// newFeed 解析对象集合
+func newFeed(objects map[string]map[string]json.RawMessage) (*Feed, error) {
+	feed := &Feed{}
+	var err error
+	if feed.Contest, err = decodeOne[Contest](objects[TypeContest]); err != nil {
+		return nil, err
+	}
+	if feed.State, err = decodeOne[State](objects[TypeState]); err != nil {
+		return nil, err
+	}
+	if feed.JudgementTypes, err = decodeAll[JudgementType](objects[TypeJudgementTypes]); err != nil {
+		return nil, err
+	}
+	if feed.Languages, err = decodeAll[Language](objects[TypeLanguages]); err != nil {
+		return nil, err
+	}
+	if feed.Problems, err = decodeAll[Problem](objects[TypeProblems]); err != nil {
+		return nil, err
+	}
+	if feed.Groups, err = decodeAll[Group](objects[TypeGroups]); err != nil {
+		return nil, err
+	}
+	if feed.Organizations, err = decodeAll[Organization](objects[TypeOrganizations]); err != nil {
+		return nil, err
+	}
+	if feed.Teams, err = decodeAll[Team](objects[TypeTeams]); err != nil {
+		return nil, err
+	}
+	if feed.Submissions, err = decodeAll[Submission](objects[TypeSubmissions]); err != nil {
+		return nil, err
+	}
+	if feed.Judgements, err = decodeAll[Judgement](objects[TypeJudgements]); err != nil {
+		return nil, err
+	}
+
+	if feed.Contest == nil {
+		return nil, fmt.Errorf("缺少比赛信息")
+	}
+
+	return feed, nil
+}
+
+// SortedProblems 返回按序号排序的题目
+func (f *Feed) SortedProblems() []*Problem {
+	problems := make([]*Problem, 0, len(f.Problems))
+	for _, problem := range f.Problems {
+		problems = append(problems, problem)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Ordinal != problems[j].Ordinal {
+			return problems[i].Ordinal < problems[j].Ordinal
+		}
+		return problems[i].Label < problems[j].Label
+	})
+	return problems
+}
+
+// applyEvent 将事件应用到对象集合上
+func applyEvent(objects map[string]map[string]json.RawMessage, event *Event) error {
+	typ := event.Type
+	if typ == TypeContests {
+		typ = TypeContest
+	}
+	if objects[typ] == nil {
+		objects[typ] = make(map[string]json.RawMessage)
+	}
+	collection := objects[typ]
+
+	data := bytes.TrimSpace(event.Data)
+	isNull := len(data) == 0 || bytes.Equal(data, []byte("null"))
+
+	// 新格式中 data 为数组时替换整个集合
+	if !isNull && data[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		clear(collection)
+		for _, item := range items {
+			id, err := objectId(item)
+			if err != nil {
+				return err
+			}
+			collection[id] = item
+		}
+		return nil
+	}
+
+	// 比赛和状态只有一个对象
+	singleton := typ == TypeContest || typ == TypeState
+
+	// 删除对象：旧格式 op 为 delete，新格式 data 为 null
+	if event.Op == "delete" || isNull {
+		id := ""
+		if !isNull {
+			id, _ = objectId(data)
+		} else if event.Id != nil && event.Op == "" {
+			id = *event.Id
+		}
+		if singleton {
+			clear(collection)
+		} else if id != "" {
+			delete(collection, id)
+		}
+		return nil
+	}
+
+	// 创建或更新对象
+	id := ""
+	if !singleton {
+		var err error
+		if id, err = objectId(data); err != nil {
+			return err
+		}
+	}
+	collection[id] = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// objectId 返回对象的 id
+func objectId(data json.RawMessage) (string, error) {
+	var object struct {
+		Id json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return "", err
+	}
+
+	// 兼容数字类型的 id
+	var id string
+	if err := json.Unmarshal(object.Id, &id); err != nil {
+		id = string(object.Id)
+	}
+	if id == "" || id == "null" {
+		return "", fmt.Errorf("对象缺少 id")
+	}
+	return id, nil
+}
+
+// decodeOne 解析单个对象
+func decodeOne[T any](collection map[string]json.RawMessage) (*T, error) {
+	for _, data := range collection {
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return &value, nil
+	}
+	return nil, nil
+}
+
+// decodeAll 解析集合中的所有对象
+func decodeAll[T any](collection map[string]json.RawMessage) (map[string]*T, error) {
+	result := make(map[string]*T, len(collection))
+	for id, data := range collection {
+		var value T
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", id, err)
+		}
+		result[id] = &value
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package clics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 绝对时间的格式，时区可能省略分钟
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.999Z07:00",
+	"2006-01-02T15:04:05.999Z07",
+	"2006-01-02T15:04:05.999Z0700",
+}
+
+// ParseTime 解析绝对时间，如 2014-06-25T10:00:00+01
+func ParseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间 %q", value)
+}
+
+// FormatTime 格式化绝对时间
+func FormatTime(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// ParseRelTime 解析相对时间(毫秒)，如 -1:23:45.678
+func ParseRelTime(value string) (int, error) {
+	sign := 1
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("无法解析相对时间 %q", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("无法解析相对时间 %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("无法解析相对时间 %q", value)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析相对时间 %q", value)
+	}
+
+	ms := (hours*60+minutes)*60*1000 + int(seconds*1000+0.5)
+	return sign * ms, nil
+}
+
+// FormatRelTime 格式化相对时间(毫秒)
+func FormatRelTime(ms int) string {
+	sign := ""
+	if ms < 0 {
+		sign, ms = "-", -ms
+	}
+	return fmt.Sprintf("%s%d:%02d:%02d.%03d", sign, ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,174 @@
+package clics
+
+import "encoding/json"
+
+// Contest 比赛
+type Contest struct {
+	Id                       string          `json:"id"`
+	Name                     string          `json:"name"`
+	FormalName               string          `json:"formal_name,omitempty"`
+	StartTime                *string         `json:"start_time"`
+	Duration                 string          `json:"duration"`
+	ScoreboardFreezeDuration *string         `json:"scoreboard_freeze_duration,omitempty"`
+	PenaltyTime              json.RawMessage `json:"penalty_time,omitempty"` // 旧版本为分钟数，新版本为相对时间
+	ScoreboardType           string          `json:"scoreboard_type,omitempty"`
+}
+
+// JudgementType 评测结果类型
+type JudgementType struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Penalty bool   `json:"penalty"`
+	Solved  bool   `json:"solved"`
+}
+
+// Language 编程语言
+type Language struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Problem 题目
+type Problem struct {
+	Id            string  `json:"id"`
+	Label         string  `json:"label"`
+	Name          string  `json:"name"`
+	Ordinal       int     `json:"ordinal"`
+	Color         string  `json:"color,omitempty"`
+	Rgb           string  `json:"rgb,omitempty"`
+	TimeLimit     float64 `json:"time_limit,omitempty"`
+	TestDataCount int     `json:"test_data_count,omitempty"`
+}
+
+// Group 队伍分组
+type Group struct {
+	Id     string `json:"id"`
+	IcpcId string `json:"icpc_id,omitempty"`
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// Organization 组织(学校)
+type Organization struct {
+	Id         string `json:"id"`
+	IcpcId     string `json:"icpc_id,omitempty"`
+	Name       string `json:"name"`
+	FormalName string `json:"formal_name,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// Team 队伍
+type Team struct {
+	Id             string   `json:"id"`
+	IcpcId         string   `json:"icpc_id,omitempty"`
+	Name           string   `json:"name"`
+	DisplayName    string   `json:"display_name,omitempty"`
+	OrganizationId string   `json:"organization_id,omitempty"`
+	GroupIds       []string `json:"group_ids"`
+	Hidden         bool     `json:"hidden,omitempty"`
+}
+
+// Submission 提交
+type Submission struct {
+	Id          string `json:"id"`
+	LanguageId  string `json:"language_id"`
+	ProblemId   string `json:"problem_id"`
+	TeamId      string `json:"team_id"`
+	Time        string `json:"time"`
+	ContestTime string `json:"contest_time"`
+}
+
+// Judgement 评测
+type Judgement struct {
+	Id               string  `json:"id"`
+	SubmissionId     string  `json:"submission_id"`
+	JudgementTypeId  *string `json:"judgement_type_id"`
+	StartTime        string  `json:"start_time,omitempty"`
+	StartContestTime string  `json:"start_contest_time,omitempty"`
+	EndTime          *string `json:"end_time,omitempty"`
+	EndContestTime   *string `json:"end_contest_time,omitempty"`
+	Valid            *bool   `json:"valid,omitempty"`
+}
+
+// IsValid 判断评测是否有效，未指定时视为有效
+func (j *Judgement) IsValid() bool {
+	return j.Valid == nil || *j.Valid
+}
+
+// State 比赛状态
+type State struct {
+	Started      *string `json:"started"`
+	Frozen       *string `json:"frozen,omitempty"`
+	Ended        *string `json:"ended"`
+	Thawed       *string `json:"thawed,omitempty"`
+	Finalized    *string `json:"finalized"`
+	EndOfUpdates *string `json:"end_of_updates"`
+}
+
+// Event event-feed 中的一个事件
+//
+// 兼容两种格式：
+//   - 2020-03 及之前：{"id": 事件 id, "type": 类型, "op": create/update/delete, "data": 对象}
+//   - 2022-07 及之后：{"type": 类型, "id": 对象 id, "data": 对象、对象数组或 null, "token": 令牌}
+type Event struct {
+	Id    *string         `json:"id"`
+	Type  string          `json:"type"`
+	Op    string          `json:"op,omitempty"`
+	Data  json.RawMessage `json:"data"`
+	Token string          `json:"token,omitempty"`
+}
+
+// 事件类型
+const (
+	TypeContest        = "contest"
+	TypeContests       = "contests"
+	TypeState          = "state"
+	TypeJudgementTypes = "judgement-types"
+	TypeLanguages      = "languages"
+	TypeProblems       = "problems"
+	TypeGroups         = "groups"
+	TypeOrganizations  = "organizations"
+	TypeTeams          = "teams"
+	TypeSubmissions    = "submissions"
+	TypeJudgements     = "judgements"
+)
+
+// Scoreboard 排行榜
+type Scoreboard struct {
+	Time        string           `json:"time"`
+	ContestTime string           `json:"contest_time"`
+	State       *State           `json:"state"`
+	Rows        []*ScoreboardRow `json:"rows"`
+}
+
+// ScoreboardRow 排行榜中的一行
+type ScoreboardRow struct {
+	Rank     int                  `json:"rank"`
+	TeamId   string               `json:"team_id"`
+	Score    Score                `json:"score"`
+	Problems []*ScoreboardProblem `json:"problems"`
+}
+
+// Score 队伍成绩
+type Score struct {
+	NumSolved int `json:"num_solved"`
+	TotalTime int `json:"total_time"`
+}
+
+// ScoreboardProblem 队伍在一道题目上的成绩
+type ScoreboardProblem struct {
+	ProblemId    string `json:"problem_id"`
+	NumJudged    int    `json:"num_judged"`
+	NumPending   int    `json:"num_pending"`
+	Solved       bool   `json:"solved"`
+	Time         int    `json:"time,omitempty"`
+	FirstToSolve bool   `json:"first_to_solve,omitempty"`
+}
+
+// Award 奖项
+type Award struct {
+	Id       string   `json:"id"`
+	Citation string   `json:"citation"`
+	TeamIds  []string `json:"team_ids"`
+}
@@ -0,0 +1,77 @@
+package clics
+
+// 评测结果类型 id 与提交状态的对应关系
+var verdicts = map[string]string{
+	"AC":  "ACCEPTED",
+	"WA":  "WRONG_ANSWER",
+	"TLE": "TIME_LIMIT_EXCEEDED",
+	"RTE": "RUNTIME_ERROR",
+	"RE":  "RUNTIME_ERROR",
+	"MLE": "MEMORY_LIMIT_EXCEEDED",
+	"OLE": "OUTPUT_LIMIT_EXCEEDED",
+	"PE":  "PRESENTATION_ERROR",
+	"NO":  "NO_OUTPUT",
+	"CE":  "COMPILATION_ERROR",
+	"JE":  "SYSTEM_ERROR",
+	"IE":  "SYSTEM_ERROR",
+	"SV":  "SECURITY_VIOLATION",
+}
+
+// StatusPending 尚未评测完成的提交状态
+const StatusPending = "PENDING"
+
+// Status 根据评测结果类型返回提交状态
+//
+// 未知的类型根据 solved 和 penalty 推断：通过视为 ACCEPTED，不计罚时视为 COMPILATION_ERROR，其余视为 WRONG_ANSWER
+func Status(judgementType *JudgementType) string {
+	if status, ok := verdicts[judgementType.Id]; ok {
+		return status
+	}
+
+	switch {
+	case judgementType.Solved:
+		return "ACCEPTED"
+	case !judgementType.Penalty:
+		return "COMPILATION_ERROR"
+	default:
+		return "WRONG_ANSWER"
+	}
+}
+
+// 提交状态与评测结果类型的对应关系，Penalty 为默认罚时规则下的取值
+var judgementTypes = []*JudgementType{
+	{Id: "AC", Name: "ACCEPTED", Penalty: false, Solved: true},
+	{Id: "WA", Name: "WRONG_ANSWER", Penalty: true, Solved: false},
+	{Id: "TLE", Name: "TIME_LIMIT_EXCEEDED", Penalty: true, Solved: false},
+	{Id: "RTE", Name: "RUNTIME_ERROR", Penalty: true, Solved: false},
+	{Id: "MLE", Name: "MEMORY_LIMIT_EXCEEDED", Penalty: true, Solved: false},
+	{Id: "OLE", Name: "OUTPUT_LIMIT_EXCEEDED", Penalty: true, Solved: false},
+	{Id: "PE", Name: "PRESENTATION_ERROR", Penalty: true, Solved: false},
+	{Id: "NO", Name: "NO_OUTPUT", Penalty: true, Solved: false},
+	{Id: "CE", Name: "COMPILATION_ERROR", Penalty: false, Solved: false},
+	{Id: "JE", Name: "SYSTEM_ERROR", Penalty: false, Solved: false},
+	{Id: "SV", Name: "SECURITY_VIOLATION", Penalty: true, Solved: false},
+}
+
+// JudgementTypes 返回所有评测结果类型
+//
+// 是否计罚时由 penaltyFree 决定，与比赛排名的罚时规则保持一致
+func JudgementTypes(penaltyFree func(status string) bool) []*JudgementType {
+	types := make([]*JudgementType, 0, len(judgementTypes))
+	for _, judgementType := range judgementTypes {
+		copied := *judgementType
+		copied.Penalty = !copied.Solved && !penaltyFree(copied.Name)
+		types = append(types, &copied)
+	}
+	return types
+}
+
+// JudgementTypeId 根据提交状态返回评测结果类型 id，未知状态视为 WA
+func JudgementTypeId(status string) string {
+	for _, judgementType := range judgementTypes {
+		if judgementType.Name == status {
+			return judgementType.Id
+		}
+	}
+	return "WA"
+}
@@ -0,0 +1,284 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lllllan02/scoreboardv2/internal/clics"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// CLICS 从 CLICS Contest API 的 event-feed 文件导入
+type CLICS struct{}
+
+// Import 实现 Importer 接口，source 为 NDJSON 格式的 event-feed 文件
+func (CLICS) Import(source string) (*Contest, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return FromEventFeed(file)
+}
+
+// FromEventFeed 从 CLICS Contest API 的 event-feed(NDJSON) 导入比赛数据
+func FromEventFeed(r io.Reader) (*Contest, error) {
+	feed, err := clics.ReadFeed(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromFeed(feed)
+}
+
+// FromFeed 将 event-feed 的数据转换为比赛数据
+//
+// 隐藏的队伍及其提交会被忽略，评测结果取最后一次有效评测，多次有效评测记为重测历史
+func FromFeed(feed *clics.Feed) (*Contest, error) {
+	config, err := feedConfig(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	contest := &Contest{
+		Config: *config,
+		Team:   feedTeams(feed),
+	}
+
+	// 题目 id -> 题目索引
+	problemIndex := make(map[string]int)
+	for index, problem := range feed.SortedProblems() {
+		problemIndex[problem.Id] = index
+	}
+
+	// 提交 id -> 按时间排序的有效评测
+	judgements := make(map[string][]*clics.Judgement)
+	for _, judgement := range sortedJudgements(feed) {
+		if judgement.IsValid() {
+			judgements[judgement.SubmissionId] = append(judgements[judgement.SubmissionId], judgement)
+		}
+	}
+
+	contest.Run = make(model.RunList, 0, len(feed.Submissions))
+	for _, submission := range feed.Submissions {
+		// 忽略隐藏队伍和未知题目的提交
+		if _, ok := contest.Team[submission.TeamId]; !ok {
+			continue
+		}
+		index, ok := problemIndex[submission.ProblemId]
+		if !ok {
+			continue
+		}
+
+		timestamp, err := clics.ParseRelTime(submission.ContestTime)
+		if err != nil {
+			return nil, fmt.Errorf("提交 %s: %w", submission.Id, err)
+		}
+
+		language := submission.LanguageId
+		if l, ok := feed.Languages[language]; ok && l.Name != "" {
+			language = l.Name
+		}
+
+		run := model.Run{
+			Status:       model.VerdictPending,
+			TeamId:       model.FlexString(submission.TeamId),
+			ProblemId:    index,
+			Timestamp:    timestamp,
+			Language:     language,
+			SubmissionId: submission.Id,
+		}
+
+		// 第一次评测的结果在提交时生效，之后的评测视为重测，在评测结束时生效
+		for i, judgement := range judgements[submission.Id] {
+			status := judgementStatus(feed, judgement)
+			if i == 0 {
+				run.Status = status
+			} else {
+				run.Rejudge(status, judgementTime(judgement))
+			}
+		}
+
+		contest.Run = append(contest.Run, run)
+	}
+
+	// 按提交时间排序
+	sort.SliceStable(contest.Run, func(i, j int) bool {
+		if contest.Run[i].Timestamp != contest.Run[j].Timestamp {
+			return contest.Run[i].Timestamp < contest.Run[j].Timestamp
+		}
+		return contest.Run[i].SubmissionId < contest.Run[j].SubmissionId
+	})
+
+	return contest, nil
+}
+
+// feedConfig 转换比赛配置
+func feedConfig(feed *clics.Feed) (*model.ContestConfig, error) {
+	contest := feed.Contest
+
+	config := &model.ContestConfig{
+		ContestName: contest.FormalName,
+		Group:       make(map[string]string),
+		Options: model.Options{
+			CalculationOfPenalty: model.PenaltyInMinutes,
+		},
+	}
+	if config.ContestName == "" {
+		config.ContestName = contest.Name
+	}
+
+	// 比赛时间
+	duration, err := clics.ParseRelTime(contest.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("比赛时长: %w", err)
+	}
+	if contest.StartTime != nil {
+		start, err := clics.ParseTime(*contest.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("比赛开始时间: %w", err)
+		}
+		config.StartTime = start.Unix()
+		config.EndTime = config.StartTime + int64(duration/1000)
+	}
+
+	// 封榜时长
+	if contest.ScoreboardFreezeDuration != nil {
+		frozen, err := clics.ParseRelTime(*contest.ScoreboardFreezeDuration)
+		if err != nil {
+			return nil, fmt.Errorf("封榜时长: %w", err)
+		}
+		config.FrozenTime = frozen / 1000
+	}
+
+	// 罚时：旧版本为分钟数，新版本为相对时间
+	if penalty := strings.Trim(string(contest.PenaltyTime), `"`); penalty != "" && penalty != "null" {
+		if minutes, err := strconv.Atoi(penalty); err == nil {
+			config.Penalty = minutes * 60
+		} else if ms, err := clics.ParseRelTime(penalty); err == nil {
+			config.Penalty = ms / 1000
+		} else {
+			return nil, fmt.Errorf("罚时: 无法解析 %s", penalty)
+		}
+	}
+
+	// 题目
+	for _, problem := range feed.SortedProblems() {
+		config.ProblemId = append(config.ProblemId, problem.Label)
+		if problem.Rgb != "" {
+			config.BalloonColor = append(config.BalloonColor, model.BalloonColor{
+				Color:           "#000",
+				BackgroundColor: problem.Rgb,
+			})
+		}
+	}
+	config.ProblemQuantity = len(config.ProblemId)
+	// 气球颜色不完整时不使用
+	if len(config.BalloonColor) != config.ProblemQuantity {
+		config.BalloonColor = nil
+	}
+
+	// 分组
+	for _, group := range feed.Groups {
+		if !group.Hidden {
+			config.Group[group.Id] = group.Name
+		}
+	}
+
+	return config, nil
+}
+
+// feedTeams 转换队伍列表，隐藏的队伍会被忽略，属于隐藏分组或观察者分组的队伍为非正式队伍
+func feedTeams(feed *clics.Feed) model.TeamList {
+	teams := make(model.TeamList)
+	for _, team := range feed.Teams {
+		if team.Hidden {
+			continue
+		}
+
+		name := team.DisplayName
+		if name == "" {
+			name = team.Name
+		}
+
+		organization := ""
+		if org, ok := feed.Organizations[team.OrganizationId]; ok {
+			organization = org.FormalName
+			if organization == "" {
+				organization = org.Name
+			}
+		}
+
+		official := feedTeamOfficial(feed, team)
+		teams[team.Id] = model.Team{
+			TeamId:       model.FlexString(team.Id),
+			Name:         model.FlexString(name),
+			Organization: organization,
+			Group:        team.GroupIds,
+			Official:     model.FlexBool(official),
+			Unofficial:   model.FlexBool(!official),
+		}
+	}
+	return teams
+}
+
+// feedTeamOfficial 判断队伍是否为正式队伍，属于隐藏分组或观察者分组的队伍不是正式队伍
+func feedTeamOfficial(feed *clics.Feed, team *clics.Team) bool {
+	for _, id := range team.GroupIds {
+		group, ok := feed.Groups[id]
+		if !ok {
+			continue
+		}
+		if group.Hidden || strings.EqualFold(group.Type, "observer") || strings.Contains(strings.ToLower(group.Name), "observer") {
+			return false
+		}
+	}
+	return true
+}
+
+// judgementTime 返回评测结束的相对时间(毫秒)，未结束时为开始时间
+func judgementTime(judgement *clics.Judgement) int {
+	if judgement.EndContestTime != nil {
+		t, _ := clics.ParseRelTime(*judgement.EndContestTime)
+		return t
+	}
+	t, _ := clics.ParseRelTime(judgement.StartContestTime)
+	return t
+}
+
+// sortedJudgements 返回按评测结束时间排序的评测
+func sortedJudgements(feed *clics.Feed) []*clics.Judgement {
+	judgements := make([]*clics.Judgement, 0, len(feed.Judgements))
+	endTime := make(map[string]int, len(feed.Judgements))
+	for _, judgement := range feed.Judgements {
+		judgements = append(judgements, judgement)
+		endTime[judgement.Id] = judgementTime(judgement)
+	}
+
+	sort.SliceStable(judgements, func(i, j int) bool {
+		a, b := judgements[i], judgements[j]
+		if endTime[a.Id] != endTime[b.Id] {
+			return endTime[a.Id] < endTime[b.Id]
+		}
+		return a.Id < b.Id
+	})
+	return judgements
+}
+
+// judgementStatus 返回评测对应的评测结果，没有评测结果时为 PENDING
+func judgementStatus(feed *clics.Feed, judgement *clics.Judgement) model.Verdict {
+	if judgement == nil || judgement.JudgementTypeId == nil {
+		return model.VerdictPending
+	}
+
+	judgementType, ok := feed.JudgementTypes[*judgement.JudgementTypeId]
+	if !ok {
+		judgementType = &clics.JudgementType{Id: *judgement.JudgementTypeId, Penalty: true}
+	}
+	return model.NormalizeVerdict(clics.Status(judgementType))
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+func TestCLICSImport(t *testing.T) {
+	contest, err := CLICS{}.Import("testdata/event-feed.ndjson")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	start := time.Date(2024, 11, 24, 9, 0, 0, 0, time.UTC).Unix()
+	wantConfig := model.ContestConfig{
+		ContestName:     "NWERC 2024",
+		StartTime:       start,
+		EndTime:         start + 5*60*60,
+		FrozenTime:      60 * 60,
+		Penalty:         20 * 60,
+		ProblemQuantity: 1,
+		ProblemId:       []string{"A"},
+		Group:           map[string]string{"participants": "Participants", "observers": "Observers"},
+		Options:         model.Options{CalculationOfPenalty: model.PenaltyInMinutes},
+	}
+	if !reflect.DeepEqual(contest.Config, wantConfig) {
+		t.Errorf("Config = %+v, want %+v", contest.Config, wantConfig)
+	}
+
+	// 隐藏的队伍被忽略，观察者分组和隐藏分组的队伍为非正式队伍
+	wantTeam := model.TeamList{
+		"1": {TeamId: "1", Name: "Team One", Organization: "University of Amsterdam", Group: []string{"participants"}, Official: true},
+		"2": {TeamId: "2", Name: "Guests", Group: []string{"observers"}, Unofficial: true},
+		"3": {TeamId: "3", Name: "Jury", Group: []string{"staff"}, Unofficial: true},
+	}
+	if !reflect.DeepEqual(contest.Team, wantTeam) {
+		t.Errorf("Team = %+v, want %+v", contest.Team, wantTeam)
+	}
+
+	// 第二次评测记为重测，在评测结束时生效
+	wantRun := model.RunList{
+		{
+			Status:       model.VerdictAccepted,
+			TeamId:       "1",
+			ProblemId:    0,
+			Timestamp:    900000,
+			Language:     "Java",
+			SubmissionId: "s1",
+			History: []model.Judgement{
+				{Status: model.VerdictWrongAnswer, Timestamp: 900000},
+				{Status: model.VerdictAccepted, Timestamp: 7205000},
+			},
+		},
+	}
+	if !reflect.DeepEqual(contest.Run, wantRun) {
+		t.Errorf("Run = %+v, want %+v", contest.Run, wantRun)
+	}
+}
@@ -0,0 +1,303 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// Codeforces 从 Codeforces(gym) API 的响应导入
+//
+// source 可以是 contest.standings 的响应文件，也可以是包含 standings.json 和可选 status.json(contest.status 的响应) 的目录。
+// 有 status.json 时使用真实的提交记录；否则根据榜单生成提交，未通过题目的错误提交时间未知，记为比赛结束时刻。
+type Codeforces struct{}
+
+// cfResponse Codeforces API 响应
+type cfResponse[T any] struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+	Result  T      `json:"result"`
+}
+
+// cfStandings contest.standings 的结果
+type cfStandings struct {
+	Contest  cfContest   `json:"contest"`
+	Problems []cfProblem `json:"problems"`
+	Rows     []cfRow     `json:"rows"`
+}
+
+// cfContest 比赛
+type cfContest struct {
+	Id               int    `json:"id"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	DurationSeconds  int64  `json:"durationSeconds"`
+	StartTimeSeconds int64  `json:"startTimeSeconds"`
+}
+
+// cfProblem 题目
+type cfProblem struct {
+	Index string `json:"index"`
+	Name  string `json:"name"`
+}
+
+// cfParty 参赛方，可以是个人或队伍
+type cfParty struct {
+	TeamId          int        `json:"teamId"`
+	TeamName        string     `json:"teamName"`
+	Members         []cfMember `json:"members"`
+	ParticipantType string     `json:"participantType"`
+	Ghost           bool       `json:"ghost"`
+}
+
+// cfMember 参赛方成员
+type cfMember struct {
+	Handle string `json:"handle"`
+	Name   string `json:"name"`
+}
+
+// cfRow 榜单中的一行
+type cfRow struct {
+	Party          cfParty           `json:"party"`
+	ProblemResults []cfProblemResult `json:"problemResults"`
+}
+
+// cfProblemResult 参赛方在一道题目上的结果
+type cfProblemResult struct {
+	Points                    float64 `json:"points"`
+	RejectedAttemptCount      int     `json:"rejectedAttemptCount"`
+	BestSubmissionTimeSeconds int     `json:"bestSubmissionTimeSeconds"`
+}
+
+// cfSubmission 提交
+type cfSubmission struct {
+	Id                  int64     `json:"id"`
+	RelativeTimeSeconds int       `json:"relativeTimeSeconds"`
+	Problem             cfProblem `json:"problem"`
+	Author              cfParty   `json:"author"`
+	ProgrammingLanguage string    `json:"programmingLanguage"`
+	Verdict             string    `json:"verdict"`
+}
+
+// Codeforces 特有的评测结果，其余评测结果按通用写法规范化
+var cfVerdicts = map[string]model.Verdict{
+	"PARTIAL":                   model.VerdictWrongAnswer,
+	"CHALLENGED":                model.VerdictWrongAnswer,
+	"REJECTED":                  model.VerdictWrongAnswer,
+	"SKIPPED":                   model.VerdictWrongAnswer,
+	"INPUT_PREPARATION_CRASHED": model.VerdictSystemError,
+}
+
+// Import 实现 Importer 接口
+func (Codeforces) Import(source string) (*Contest, error) {
+	standingsFile, statusFile := source, ""
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		standingsFile = filepath.Join(source, "standings.json")
+		statusFile = filepath.Join(source, "status.json")
+		if _, err := os.Stat(statusFile); err != nil {
+			statusFile = ""
+		}
+	}
+
+	standings, err := readCodeforces[cfStandings](standingsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	contest := &Contest{
+		Config: cfConfig(standings),
+		Team:   make(model.TeamList),
+	}
+
+	// 题目编号 -> 题目索引
+	problemIndex := make(map[string]int, len(standings.Problems))
+	for index, problem := range standings.Problems {
+		problemIndex[problem.Index] = index
+	}
+
+	for _, row := range standings.Rows {
+		if team, ok := cfTeam(row.Party); ok {
+			contest.Team[string(team.TeamId)] = team
+		}
+	}
+
+	if statusFile != "" {
+		submissions, err := readCodeforces[[]cfSubmission](statusFile)
+		if err != nil {
+			return nil, err
+		}
+		contest.Run = cfRuns(contest, problemIndex, submissions)
+	} else {
+		contest.Run = cfStandingRuns(contest, standings)
+	}
+	sortRuns(contest.Run)
+
+	return contest, nil
+}
+
+// readCodeforces 读取 Codeforces API 响应文件
+func readCodeforces[T any](path string) (T, error) {
+	var response cfResponse[T]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return response.Result, err
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return response.Result, fmt.Errorf("%s 解析失败: %w", path, err)
+	}
+	if response.Status != "" && response.Status != "OK" {
+		return response.Result, fmt.Errorf("%s: %s", path, response.Comment)
+	}
+
+	return response.Result, nil
+}
+
+// cfConfig 转换比赛配置，罚时按 ICPC 规则为 20 分钟
+func cfConfig(standings cfStandings) model.ContestConfig {
+	config := model.ContestConfig{
+		ContestName:     standings.Contest.Name,
+		StartTime:       standings.Contest.StartTimeSeconds,
+		EndTime:         standings.Contest.StartTimeSeconds + standings.Contest.DurationSeconds,
+		Penalty:         model.DefaultPenalty,
+		ProblemQuantity: len(standings.Problems),
+		Options: model.Options{
+			CalculationOfPenalty: model.PenaltyInMinutes,
+		},
+	}
+	for _, problem := range standings.Problems {
+		config.ProblemId = append(config.ProblemId, problem.Index)
+	}
+	return config
+}
+
+// cfTeam 转换队伍，只保留正式参赛和非正式参赛(打星)的参赛方
+func cfTeam(party cfParty) (model.Team, bool) {
+	if party.ParticipantType != "CONTESTANT" && party.ParticipantType != "OUT_OF_COMPETITION" {
+		return model.Team{}, false
+	}
+
+	handles := make([]string, 0, len(party.Members))
+	for _, member := range party.Members {
+		handles = append(handles, member.Handle)
+	}
+
+	name := party.TeamName
+	if name == "" {
+		name = strings.Join(handles, ", ")
+	}
+
+	team := model.Team{
+		TeamId:  model.FlexString(cfPartyId(party)),
+		Name:    model.FlexString(name),
+		Members: handles,
+	}
+	if party.ParticipantType == "OUT_OF_COMPETITION" {
+		team.Unofficial = true
+		team.Group = []string{model.GroupUnofficial}
+	} else {
+		team.Official = true
+		team.Group = []string{model.GroupOfficial}
+	}
+	return team, true
+}
+
+// cfPartyId 返回参赛方 id：队伍使用队伍 id，个人使用用户名，幽灵队伍使用队名
+func cfPartyId(party cfParty) string {
+	if party.TeamId > 0 {
+		return strconv.Itoa(party.TeamId)
+	}
+
+	handles := make([]string, 0, len(party.Members))
+	for _, member := range party.Members {
+		handles = append(handles, member.Handle)
+	}
+	if len(handles) > 0 {
+		return strings.Join(handles, ",")
+	}
+	return party.TeamName
+}
+
+// cfRuns 转换真实的提交记录，忽略未知队伍、未知题目和赛后的提交
+func cfRuns(contest *Contest, problemIndex map[string]int, submissions []cfSubmission) model.RunList {
+	duration := int(contest.Config.EndTime - contest.Config.StartTime)
+
+	runs := make(model.RunList, 0, len(submissions))
+	for _, submission := range submissions {
+		teamId := cfPartyId(submission.Author)
+		if _, ok := contest.Team[teamId]; !ok {
+			continue
+		}
+		index, ok := problemIndex[submission.Problem.Index]
+		if !ok || submission.RelativeTimeSeconds > duration {
+			continue
+		}
+		status, ok := cfVerdicts[submission.Verdict]
+		if !ok {
+			status = model.NormalizeVerdict(submission.Verdict)
+		}
+
+		runs = append(runs, model.Run{
+			Status:       status,
+			TeamId:       model.FlexString(teamId),
+			ProblemId:    index,
+			Timestamp:    submission.RelativeTimeSeconds * 1000,
+			Language:     submission.ProgrammingLanguage,
+			SubmissionId: strconv.FormatInt(submission.Id, 10),
+		})
+	}
+	return runs
+}
+
+// cfStandingRuns 根据榜单生成提交记录
+//
+// 通过的题目：错误提交和通过提交都记在通过时刻，保证罚时和榜单一致；
+// 未通过的题目：错误提交记在比赛结束时刻。
+func cfStandingRuns(contest *Contest, standings cfStandings) model.RunList {
+	end := int(contest.Config.EndTime-contest.Config.StartTime) * 1000
+
+	runs := make(model.RunList, 0)
+	for _, row := range standings.Rows {
+		teamId := cfPartyId(row.Party)
+		if _, ok := contest.Team[teamId]; !ok {
+			continue
+		}
+
+		for index, result := range row.ProblemResults {
+			solved := result.Points > 0
+			timestamp := end
+			if solved {
+				timestamp = result.BestSubmissionTimeSeconds * 1000
+			}
+
+			for i := 0; i < result.RejectedAttemptCount; i++ {
+				runs = append(runs, model.Run{
+					Status:    model.VerdictWrongAnswer,
+					TeamId:    model.FlexString(teamId),
+					ProblemId: index,
+					Timestamp: timestamp,
+				})
+			}
+			if solved {
+				runs = append(runs, model.Run{
+					Status:    model.VerdictAccepted,
+					TeamId:    model.FlexString(teamId),
+					ProblemId: index,
+					Timestamp: timestamp,
+				})
+			}
+		}
+	}
+
+	// 生成的提交按时间顺序编号
+	sortRuns(runs)
+	for i := range runs {
+		runs[i].SubmissionId = strconv.Itoa(i + 1)
+	}
+	return runs
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+func TestCodeforcesImport(t *testing.T) {
+	wantConfig := model.ContestConfig{
+		ContestName:     "Test Round",
+		StartTime:       1700000000,
+		EndTime:         1700018000,
+		Penalty:         model.DefaultPenalty,
+		ProblemQuantity: 2,
+		ProblemId:       []string{"A", "B"},
+		Options:         model.Options{CalculationOfPenalty: model.PenaltyInMinutes},
+	}
+	wantTeam := model.TeamList{
+		"11": {
+			TeamId:   "11",
+			Name:     "Alpha",
+			Members:  []string{"alice", "amy"},
+			Group:    []string{model.GroupOfficial},
+			Official: true,
+		},
+		"bob": {
+			TeamId:     "bob",
+			Name:       "bob",
+			Members:    []string{"bob"},
+			Group:      []string{model.GroupUnofficial},
+			Unofficial: true,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   model.RunList
+	}{
+		{
+			// 只有榜单：错误提交和通过提交记在通过时刻，未通过题目的错误提交记在比赛结束时刻
+			name:   "standings",
+			source: "testdata/codeforces/standings.json",
+			want: model.RunList{
+				{Status: model.VerdictWrongAnswer, TeamId: "11", ProblemId: 0, Timestamp: 600000, SubmissionId: "1"},
+				{Status: model.VerdictAccepted, TeamId: "11", ProblemId: 0, Timestamp: 600000, SubmissionId: "2"},
+				{Status: model.VerdictAccepted, TeamId: "bob", ProblemId: 1, Timestamp: 1200000, SubmissionId: "3"},
+				{Status: model.VerdictWrongAnswer, TeamId: "11", ProblemId: 1, Timestamp: 18000000, SubmissionId: "4"},
+				{Status: model.VerdictWrongAnswer, TeamId: "11", ProblemId: 1, Timestamp: 18000000, SubmissionId: "5"},
+			},
+		},
+		{
+			// 有 status.json：使用真实提交，忽略练习、未知题目和赛后的提交
+			name:   "status",
+			source: "testdata/codeforces",
+			want: model.RunList{
+				{Status: model.VerdictWrongAnswer, TeamId: "11", ProblemId: 0, Timestamp: 300000, Language: "GNU C++17", SubmissionId: "100"},
+				{Status: model.VerdictAccepted, TeamId: "11", ProblemId: 0, Timestamp: 600000, Language: "GNU C++17", SubmissionId: "101"},
+				{Status: model.VerdictWrongAnswer, TeamId: "bob", ProblemId: 1, Timestamp: 1200000, Language: "Java 21", SubmissionId: "102"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contest, err := Codeforces{}.Import(tt.source)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !reflect.DeepEqual(contest.Config, wantConfig) {
+				t.Errorf("Config = %+v, want %+v", contest.Config, wantConfig)
+			}
+			if !reflect.DeepEqual(contest.Team, wantTeam) {
+				t.Errorf("Team = %+v, want %+v", contest.Team, wantTeam)
+			}
+			if !reflect.DeepEqual(contest.Run, tt.want) {
+				t.Errorf("Run = %+v, want %+v", contest.Run, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeforcesImportError(t *testing.T) {
+	if _, err := (Codeforces{}).Import("testdata/codeforces/missing.json"); err == nil {
+		t.Error("Import() of a missing file should fail")
+	}
+}
@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lllllan02/scoreboardv2/internal/clics"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// CSV 从通用的提交记录 CSV 文件导入
+//
+// 第一行为表头，列名不区分大小写：
+//   - team_id(必需)：队伍 id
+//   - problem(必需)：题目编号，如 A、B
+//   - time(必需)：提交的比赛时间，h:mm:ss[.uuu] 或秒数
+//   - status(必需)：提交状态，如 ACCEPTED、WRONG_ANSWER、AC、WA
+//   - team_name、organization、language、submission_id(可选)
+//
+// 文件中没有比赛信息：比赛名称为文件名，时长为最后一次提交所在的整小时，可通过 Options 覆盖。
+type CSV struct{}
+
+// CSV 中的列
+const (
+	csvTeamId       = "team_id"
+	csvTeamName     = "team_name"
+	csvOrganization = "organization"
+	csvProblem      = "problem"
+	csvTime         = "time"
+	csvStatus       = "status"
+	csvLanguage     = "language"
+	csvSubmissionId = "submission_id"
+)
+
+// 必需的列
+var csvRequired = []string{csvTeamId, csvProblem, csvTime, csvStatus}
+
+// Import 实现 Importer 接口
+func (CSV) Import(source string) (*Contest, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s 为空", source)
+	}
+
+	// 列名 -> 列索引
+	columns := make(map[string]int)
+	for index, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	for _, name := range csvRequired {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s 缺少 %s 列", source, name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if index, ok := columns[name]; ok && index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+
+	contest := &Contest{Team: make(model.TeamList)}
+	problems := make([]string, 0)
+	problemIndex := make(map[string]int)
+	for line, record := range records[1:] {
+		teamId := field(record, csvTeamId)
+		if teamId == "" {
+			continue
+		}
+
+		timestamp, err := parseCSVTime(field(record, csvTime))
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", line+2, err)
+		}
+
+		// 首次出现的队伍
+		if _, ok := contest.Team[teamId]; !ok {
+			name := field(record, csvTeamName)
+			if name == "" {
+				name = teamId
+			}
+			contest.Team[teamId] = model.Team{
+				TeamId:       model.FlexString(teamId),
+				Name:         model.FlexString(name),
+				Organization: field(record, csvOrganization),
+				Official:     true,
+			}
+		}
+
+		// 首次出现的题目
+		problem := field(record, csvProblem)
+		if _, ok := problemIndex[problem]; !ok {
+			problemIndex[problem] = len(problems)
+			problems = append(problems, problem)
+		}
+
+		submissionId := field(record, csvSubmissionId)
+		if submissionId == "" {
+			submissionId = strconv.Itoa(line + 1)
+		}
+
+		contest.Run = append(contest.Run, model.Run{
+			Status:       model.NormalizeVerdict(field(record, csvStatus)),
+			TeamId:       model.FlexString(teamId),
+			ProblemId:    problemIndex[problem],
+			Timestamp:    timestamp,
+			Language:     field(record, csvLanguage),
+			SubmissionId: submissionId,
+		})
+	}
+
+	// 题目按编号排序，如 A、B、...、Z、AA
+	sorted := append([]string(nil), problems...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) < len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	order := make(map[int]int, len(sorted))
+	for index, problem := range sorted {
+		order[problemIndex[problem]] = index
+	}
+	for i := range contest.Run {
+		contest.Run[i].ProblemId = order[contest.Run[i].ProblemId]
+	}
+	sortRuns(contest.Run)
+
+	// 比赛时长取最后一次提交所在的整小时
+	duration := int64(0)
+	if len(contest.Run) > 0 {
+		hours := contest.Run[len(contest.Run)-1].Timestamp/(60*60*1000) + 1
+		duration = int64(hours) * 60 * 60
+	}
+
+	contest.Config = model.ContestConfig{
+		ContestName:     strings.TrimSuffix(filepath.Base(source), filepath.Ext(source)),
+		EndTime:         duration,
+		Penalty:         model.DefaultPenalty,
+		ProblemQuantity: len(sorted),
+		ProblemId:       sorted,
+		Options: model.Options{
+			CalculationOfPenalty: model.PenaltyInMinutes,
+		},
+	}
+
+	return contest, nil
+}
+
+// parseCSVTime 解析提交时间(毫秒)，支持 h:mm:ss[.uuu] 和秒数
+func parseCSVTime(value string) (int, error) {
+	if strings.Contains(value, ":") {
+		return clics.ParseRelTime(value)
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析提交时间 %s", value)
+	}
+	return int(seconds * 1000), nil
+}
@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+func TestCSVImport(t *testing.T) {
+	contest, err := CSV{}.Import("testdata/submissions.csv")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	// 没有比赛信息：开始时间为 0，结束时间为时长，取最后一次提交所在的整小时
+	wantConfig := model.ContestConfig{
+		ContestName:     "submissions",
+		StartTime:       0,
+		EndTime:         3 * 60 * 60,
+		Penalty:         model.DefaultPenalty,
+		ProblemQuantity: 3,
+		ProblemId:       []string{"A", "B", "AA"},
+		Options:         model.Options{CalculationOfPenalty: model.PenaltyInMinutes},
+	}
+	if !reflect.DeepEqual(contest.Config, wantConfig) {
+		t.Errorf("Config = %+v, want %+v", contest.Config, wantConfig)
+	}
+	if got := contest.Config.Duration(); got != 3*60*60*1000 {
+		t.Errorf("Duration() = %d, want %d", got, 3*60*60*1000)
+	}
+
+	wantTeam := model.TeamList{
+		"t1": {TeamId: "t1", Name: "Team One", Organization: "Uni A", Official: true},
+		"t2": {TeamId: "t2", Name: "t2", Organization: "Uni B", Official: true},
+	}
+	if !reflect.DeepEqual(contest.Team, wantTeam) {
+		t.Errorf("Team = %+v, want %+v", contest.Team, wantTeam)
+	}
+
+	wantRun := model.RunList{
+		{Status: model.VerdictWrongAnswer, TeamId: "t2", ProblemId: 0, Timestamp: 300000, Language: "Java", SubmissionId: "2"},
+		{Status: model.VerdictAccepted, TeamId: "t1", ProblemId: 1, Timestamp: 600000, Language: "C++", SubmissionId: "1"},
+		{Status: model.VerdictAccepted, TeamId: "t1", ProblemId: 0, Timestamp: 3930500, Language: "C++", SubmissionId: "3"},
+		{Status: model.VerdictWrongAnswer, TeamId: "t2", ProblemId: 2, Timestamp: 7200000, Language: "Python", SubmissionId: "5"},
+	}
+	if !reflect.DeepEqual(contest.Run, wantRun) {
+		t.Errorf("Run = %+v, want %+v", contest.Run, wantRun)
+	}
+}
+
+func TestCSVImportError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: ""},
+		{name: "missing column", content: "team_id,problem,time\nt1,A,0:01:00\n"},
+		{name: "bad time", content: "team_id,problem,time,status\nt1,A,soon,AC\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := filepath.Join(t.TempDir(), "runs.csv")
+			if err := os.WriteFile(source, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := (CSV{}).Import(source); err == nil {
+				t.Error("Import() should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lllllan02/scoreboardv2/internal/clics"
+)
+
+// DOMjudge 从 DOMjudge 的比赛导出目录导入
+//
+// 目录中为 Contest API 各端点的 JSON 文件(contest.json、teams.json、submissions.json 等)，
+// 没有 contest.json 时读取目录中的 event-feed.ndjson 或 event-feed.json。
+type DOMjudge struct{}
+
+// Import 实现 Importer 接口，source 为导出目录
+func (DOMjudge) Import(source string) (*Contest, error) {
+	if _, err := os.Stat(filepath.Join(source, "contest.json")); err == nil {
+		feed, err := clics.ReadDir(source)
+		if err != nil {
+			return nil, err
+		}
+		return FromFeed(feed)
+	}
+
+	for _, name := range []string{"event-feed.ndjson", "event-feed.json"} {
+		path := filepath.Join(source, name)
+		if _, err := os.Stat(path); err == nil {
+			return CLICS{}.Import(path)
+		}
+	}
+
+	return nil, fmt.Errorf("%s 中没有 contest.json 或 event-feed", source)
+}
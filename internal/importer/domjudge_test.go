@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+func TestDOMjudgeImport(t *testing.T) {
+	contest, err := DOMjudge{}.Import("testdata/domjudge")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	start := time.Date(2024, 4, 18, 10, 0, 0, 0, time.UTC).Unix()
+	wantConfig := model.ContestConfig{
+		ContestName:     "World Finals 2024",
+		StartTime:       start,
+		EndTime:         start + 5*60*60,
+		FrozenTime:      60 * 60,
+		Penalty:         20 * 60,
+		ProblemQuantity: 2,
+		ProblemId:       []string{"A", "B"},
+		Group:           map[string]string{"3": "Participants"},
+		BalloonColor: []model.BalloonColor{
+			{Color: "#000", BackgroundColor: "#00ff00"},
+			{Color: "#000", BackgroundColor: "#ff0000"},
+		},
+		Options: model.Options{CalculationOfPenalty: model.PenaltyInMinutes},
+	}
+	if !reflect.DeepEqual(contest.Config, wantConfig) {
+		t.Errorf("Config = %+v, want %+v", contest.Config, wantConfig)
+	}
+
+	// 隐藏的队伍被忽略
+	wantTeam := model.TeamList{
+		"t1": {
+			TeamId:       "t1",
+			Name:         "Team One",
+			Organization: "Massachusetts Institute of Technology",
+			Group:        []string{"3"},
+			Official:     true,
+		},
+	}
+	if !reflect.DeepEqual(contest.Team, wantTeam) {
+		t.Errorf("Team = %+v, want %+v", contest.Team, wantTeam)
+	}
+
+	// 第二次评测记为重测，没有评测结果的提交为 PENDING
+	wantRun := model.RunList{
+		{
+			Status:       model.VerdictAccepted,
+			TeamId:       "t1",
+			ProblemId:    0,
+			Timestamp:    600000,
+			Language:     "C++",
+			SubmissionId: "s1",
+			History: []model.Judgement{
+				{Status: model.VerdictWrongAnswer, Timestamp: 600000},
+				{Status: model.VerdictAccepted, Timestamp: 3610000},
+			},
+		},
+		{Status: model.VerdictPending, TeamId: "t1", ProblemId: 1, Timestamp: 1200000, Language: "C++", SubmissionId: "s2"},
+	}
+	if !reflect.DeepEqual(contest.Run, wantRun) {
+		t.Errorf("Run = %+v, want %+v", contest.Run, wantRun)
+	}
+}
+
+func TestDOMjudgeImportError(t *testing.T) {
+	if _, err := (DOMjudge{}).Import(t.TempDir()); err == nil {
+		t.Error("Import() of a directory without contest.json or event-feed should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/files"
+)
+
+// Importer 比赛数据导入器，将外部数据源转换为比赛数据
+type Importer interface {
+	// Import 从数据源导入比赛数据，source 为文件或目录路径
+	Import(source string) (*Contest, error)
+}
+
+// 已注册的导入器：格式 -> 导入器
+var importers = map[string]Importer{
+	"clics":      CLICS{},
+	"domjudge":   DOMjudge{},
+	"codeforces": Codeforces{},
+	"csv":        CSV{},
+}
+
+// New 返回指定格式的导入器
+func New(format string) (Importer, error) {
+	importer, ok := importers[format]
+	if !ok {
+		return nil, fmt.Errorf("不支持的导入格式 %s，可选: %v", format, Formats())
+	}
+	return importer, nil
+}
+
+// Formats 返回所有支持的导入格式
+func Formats() []string {
+	formats := make([]string, 0, len(importers))
+	for format := range importers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Contest 导入的比赛数据，与数据目录中的 config.json、team.json、run.json 一一对应
+type Contest struct {
+	Config model.ContestConfig
+	Team   model.TeamList
+	Run    model.RunList
+}
+
+// Options 导入选项，用于补充或覆盖数据源中的比赛信息，零值表示不覆盖
+type Options struct {
+	Name      string        // 比赛名称
+	StartTime time.Time     // 开始时间
+	Duration  time.Duration // 比赛时长
+}
+
+// Apply 使用导入选项覆盖比赛配置
+func (c *Contest) Apply(options Options) {
+	if options.Name != "" {
+		c.Config.ContestName = options.Name
+	}
+
+	duration := c.Config.EndTime - c.Config.StartTime
+	if options.Duration > 0 {
+		duration = int64(options.Duration / time.Second)
+	}
+	if !options.StartTime.IsZero() {
+		c.Config.StartTime = options.StartTime.Unix()
+	}
+	c.Config.EndTime = c.Config.StartTime + duration
+}
+
+// Save 将比赛数据保存到目录 dir
+func (c *Contest) Save(dir string) error {
+	if err := files.Save(filepath.Join(dir, "config.json"), c.Config); err != nil {
+		return err
+	}
+
+	if err := files.Save(filepath.Join(dir, "team.json"), c.Team); err != nil {
+		return err
+	}
+
+	return files.Save(filepath.Join(dir, "run.json"), c.Run)
+}
+
+// sortRuns 按提交时间排序，时间相同时保持原有顺序
+func sortRuns(runs model.RunList) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Timestamp < runs[j].Timestamp
+	})
+}
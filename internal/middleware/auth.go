@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lllllan02/scoreboardv2/config"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// AdminAuth 创建管理接口的鉴权中间件
+//
+// 令牌通过 Authorization: Bearer <token> 或 X-API-Token 请求头传递，需与配置中的某个令牌一致
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			errors.SendError(c, errors.NewUnauthorized("无效的 API 令牌"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// FrozenGuard 创建封榜保护中间件
+//
+// 未携带有效管理令牌的请求忽略 unfrozen 参数，只能看到封榜后隐藏结果的榜单
+func FrozenGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if query := c.Request.URL.Query(); query.Has("unfrozen") && !IsAdmin(c) {
+			query.Del("unfrozen")
+			c.Request.URL.RawQuery = query.Encode()
+		}
+
+		c.Next()
+	}
+}
+
+// IsAdmin 判断请求是否携带有效的管理令牌
+func IsAdmin(c *gin.Context) bool {
+	token := c.GetHeader("X-API-Token")
+	if auth := c.GetHeader("Authorization"); token == "" && strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return token != "" && validToken(token)
+}
+
+// validToken 判断令牌是否有效，使用固定时间比较避免时序攻击
+func validToken(token string) bool {
+	for _, t := range config.GetConfig().Admin.Tokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -29,17 +29,53 @@ type StatusTimeDisplay struct {
 
 // Medal 定义奖牌信息的结构体
 type Medal struct {
-	Type     string        `json:"type,omitempty"`
-	Official OfficialMedal `json:"official,omitempty"`
+	Type     string                   `json:"type,omitempty"`     // 预设类型(如 ccpc、icpc)，未配置数量时按正式队伍的 10%/20%/30% 计算
+	Official OfficialMedal            `json:"official,omitempty"` // 正式队伍奖牌
+	Group    map[string]OfficialMedal `json:"group,omitempty"`    // 组别奖牌，如 girl、undergraduate、vocational
+	Special  SpecialAward             `json:"special,omitempty"`  // 特别奖项
 }
 
 // OfficialMedal 定义正式奖牌的结构体
+//
+// 奖牌数量不为 0 时使用固定数量，否则按比例计算
 type OfficialMedal struct {
 	Gold   int `json:"gold,omitempty"`
 	Silver int `json:"silver,omitempty"`
 	Bronze int `json:"bronze,omitempty"`
+
+	GoldRatio   float64 `json:"gold_ratio,omitempty"`   // 金奖比例
+	SilverRatio float64 `json:"silver_ratio,omitempty"` // 银奖比例
+	BronzeRatio float64 `json:"bronze_ratio,omitempty"` // 铜奖比例
+	Rounding    string  `json:"rounding,omitempty"`     // 取整方式：ceil(默认)、floor、round
+	Base        string  `json:"base,omitempty"`         // 比例基数：all(默认，所有队伍)、solved(至少通过一题的队伍)
+}
+
+// IsZero 判断是否未配置奖牌
+func (m OfficialMedal) IsZero() bool {
+	return m.Gold == 0 && m.Silver == 0 && m.Bronze == 0 &&
+		m.GoldRatio == 0 && m.SilverRatio == 0 && m.BronzeRatio == 0
 }
 
+// SpecialAward 定义特别奖项的结构体
+type SpecialAward struct {
+	FirstToSolve     FlexBool `json:"first_to_solve,omitempty"`    // 一血奖
+	LastGold         FlexBool `json:"last_gold,omitempty"`         // 金牌末位奖
+	HonorableMention FlexBool `json:"honorable_mention,omitempty"` // 优胜奖，通过题目但未获得奖牌的正式队伍
+}
+
+// 奖牌取整方式
+const (
+	RoundingCeil  = "ceil"
+	RoundingFloor = "floor"
+	RoundingRound = "round"
+)
+
+// 奖牌比例基数
+const (
+	MedalBaseAll    = "all"
+	MedalBaseSolved = "solved"
+)
+
 // BalloonColor 定义气球颜色的结构体
 type BalloonColor struct {
 	Color           string `json:"color,omitempty"`
@@ -67,5 +103,102 @@ type Banner struct {
 
 // Options 定义选项的结构体
 type Options struct {
-	CalculationOfPenalty string `json:"calculation_of_penalty,omitempty"`
+	CalculationOfPenalty string            `json:"calculation_of_penalty,omitempty"`
+	PenaltyFreeStatus    []string          `json:"penalty_free_status,omitempty"` // 不计罚时也不计入尝试次数的提交状态，默认为编译错误
+	VerdictMapping       map[string]string `json:"verdict_mapping,omitempty"`     // 数据源中的评测结果 -> 规范的评测结果，优先于默认的对应关系
+}
+
+// 默认不计罚时的提交状态
+var DefaultPenaltyFreeStatus = []Verdict{VerdictCompilationError}
+
+// 罚时计算方式
+const (
+	// 按分钟计算，通过时间向下取整到分钟
+	PenaltyInMinutes = "in_minutes"
+	// 按秒计算
+	PenaltyInSeconds = "in_seconds"
+	// 按秒累计，最后向下取整到分钟
+	PenaltyAccumulateInSeconds = "accumulate_in_seconds_and_finally_to_the_minute"
+)
+
+// 默认每次错误提交的罚时(秒)
+const DefaultPenalty = 20 * 60
+
+// ProblemLabel 返回题目编号，未配置时按 A、B、…、Z、AA、AB 编号
+func (c *ContestConfig) ProblemLabel(index int) string {
+	if index >= 0 && index < len(c.ProblemId) && c.ProblemId[index] != "" {
+		return c.ProblemId[index]
+	}
+
+	label := ""
+	for n := index + 1; n > 0; n = (n - 1) / 26 {
+		label = string(rune('A'+(n-1)%26)) + label
+	}
+	return label
+}
+
+// PenaltySeconds 返回每次错误提交的罚时(秒)
+func (c *ContestConfig) PenaltySeconds() int {
+	if c.Penalty <= 0 {
+		return DefaultPenalty
+	}
+	return c.Penalty
+}
+
+// PenaltyCalculation 返回罚时计算方式
+func (c *ContestConfig) PenaltyCalculation() string {
+	switch c.Options.CalculationOfPenalty {
+	case PenaltyInSeconds, PenaltyAccumulateInSeconds:
+		return c.Options.CalculationOfPenalty
+	default:
+		return PenaltyInMinutes
+	}
+}
+
+// PenaltyFreeStatus 返回不计罚时的提交状态
+func (c *ContestConfig) PenaltyFreeStatus() []Verdict {
+	if len(c.Options.PenaltyFreeStatus) == 0 {
+		return DefaultPenaltyFreeStatus
+	}
+
+	verdicts := make([]Verdict, 0, len(c.Options.PenaltyFreeStatus))
+	for _, status := range c.Options.PenaltyFreeStatus {
+		verdicts = append(verdicts, c.NormalizeVerdict(status))
+	}
+	return verdicts
+}
+
+// NormalizeVerdict 规范化评测结果，优先使用比赛配置的对应关系
+func (c *ContestConfig) NormalizeVerdict(status string) Verdict {
+	if verdict, ok := c.Options.VerdictMapping[status]; ok {
+		return NormalizeVerdict(verdict)
+	}
+
+	// 不区分大小写和分隔符
+	key := verdictKey(status)
+	for from, verdict := range c.Options.VerdictMapping {
+		if verdictKey(from) == key {
+			return NormalizeVerdict(verdict)
+		}
+	}
+
+	return NormalizeVerdict(status)
+}
+
+// FrozenAt 返回封榜开始的相对时间(毫秒)，不封榜时返回 -1
+//
+// 封榜时长超过比赛时长时视为从比赛开始就封榜
+func (c *ContestConfig) FrozenAt() int {
+	if c.FrozenTime <= 0 || c.EndTime <= c.StartTime {
+		return -1
+	}
+	return max(int(c.EndTime-c.StartTime-int64(c.FrozenTime))*1000, 0)
+}
+
+// Duration 返回比赛时长(毫秒)，未配置比赛时间时返回 0
+func (c *ContestConfig) Duration() int {
+	if c.EndTime <= c.StartTime {
+		return 0
+	}
+	return int(c.EndTime-c.StartTime) * 1000
 }
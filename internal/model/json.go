@@ -64,6 +64,27 @@ func (m *Medal) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// 兼容以组别为键的写法，如 {"official": {...}, "girl": {...}}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		switch key {
+		case "type", "official", "group", "special":
+			continue
+		}
+
+		var medal OfficialMedal
+		if err := json.Unmarshal(value, &medal); err != nil {
+			continue
+		}
+		if temp.Group == nil {
+			temp.Group = make(map[string]OfficialMedal)
+		}
+		temp.Group[key] = medal
+	}
+
 	// 将解析结果复制回原结构体
 	*m = Medal(temp)
 	return nil
@@ -3,10 +3,90 @@ package model
 type RunList []Run
 
 type Run struct {
-	Status       string     `json:"status"`
-	TeamId       FlexString `json:"team_id"`
-	ProblemId    int        `json:"problem_id"`
-	Timestamp    int        `json:"timestamp"`
-	Language     string     `json:"language"`
-	SubmissionId string     `json:"submission_id"`
+	Status       Verdict     `json:"status"`
+	TeamId       FlexString  `json:"team_id"`
+	ProblemId    int         `json:"problem_id"`
+	Timestamp    int         `json:"timestamp"`
+	Language     string      `json:"language"`
+	SubmissionId string      `json:"submission_id"`
+	History      []Judgement `json:"history,omitempty"` // 评测历史，按时间排序，最后一条与 Status 一致；未重测的提交为空
+}
+
+// Judgement 一次评测结果
+type Judgement struct {
+	Status    Verdict `json:"status"`    // 评测结果
+	Timestamp int     `json:"timestamp"` // 结果生效的相对时间(毫秒)
+}
+
+// StatusAt 返回时刻 t 的评测结果，即 t 之前最后一次评测的结果
+func (r *Run) StatusAt(t int) Verdict {
+	if len(r.History) == 0 {
+		return r.Status
+	}
+
+	status := r.History[0].Status
+	for _, judgement := range r.History[1:] {
+		if judgement.Timestamp > t {
+			break
+		}
+		status = judgement.Status
+	}
+	return status
+}
+
+// Rejudged 判断提交是否被重测过，即出过不止一次结果
+func (r *Run) Rejudged() bool {
+	judged := 0
+	for _, judgement := range r.History {
+		if !judgement.Status.IsPending() {
+			judged++
+		}
+	}
+	return judged > 1
+}
+
+// Rejudge 重测提交，新结果从时刻 t 开始生效，t 不早于上一次评测
+func (r *Run) Rejudge(status Verdict, t int) {
+	if len(r.History) == 0 {
+		r.History = []Judgement{{Status: r.Status, Timestamp: r.Timestamp}}
+	}
+	if last := r.History[len(r.History)-1].Timestamp; t < last {
+		t = last
+	}
+
+	r.History = append(r.History, Judgement{Status: status, Timestamp: t})
+	r.Status = status
+}
+
+// Merge 按提交 id 合并提交记录，同一提交出现多次时后出现的记录视为重测
+func (l RunList) Merge() RunList {
+	merged := make(RunList, 0, len(l))
+	index := make(map[string]int, len(l)) // submission_id -> merged 中的下标
+	for _, run := range l {
+		i, ok := index[run.SubmissionId]
+		if !ok || run.SubmissionId == "" {
+			index[run.SubmissionId] = len(merged)
+			merged = append(merged, run)
+			continue
+		}
+
+		history := run.History
+		if len(history) == 0 {
+			history = []Judgement{{Status: run.Status, Timestamp: run.Timestamp}}
+		}
+		for _, judgement := range history {
+			merged[i].Rejudge(judgement.Status, judgement.Timestamp)
+		}
+	}
+	return merged
+}
+
+// Normalize 按比赛配置规范化所有提交的评测结果
+func (l RunList) Normalize(config *ContestConfig) {
+	for i := range l {
+		l[i].Status = config.NormalizeVerdict(string(l[i].Status))
+		for j := range l[i].History {
+			l[i].History[j].Status = config.NormalizeVerdict(string(l[i].History[j].Status))
+		}
+	}
 }
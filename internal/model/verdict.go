@@ -0,0 +1,139 @@
+package model
+
+import "strings"
+
+// Verdict 规范化的评测结果
+type Verdict string
+
+// 评测结果
+const (
+	VerdictAccepted            Verdict = "ACCEPTED"
+	VerdictWrongAnswer         Verdict = "WRONG_ANSWER"
+	VerdictTimeLimitExceeded   Verdict = "TIME_LIMIT_EXCEEDED"
+	VerdictMemoryLimitExceeded Verdict = "MEMORY_LIMIT_EXCEEDED"
+	VerdictOutputLimitExceeded Verdict = "OUTPUT_LIMIT_EXCEEDED"
+	VerdictRuntimeError        Verdict = "RUNTIME_ERROR"
+	VerdictPresentationError   Verdict = "PRESENTATION_ERROR"
+	VerdictNoOutput            Verdict = "NO_OUTPUT"
+	VerdictCompilationError    Verdict = "COMPILATION_ERROR"
+	VerdictSecurityViolation   Verdict = "SECURITY_VIOLATION"
+	VerdictSystemError         Verdict = "SYSTEM_ERROR"
+	VerdictPending             Verdict = "PENDING" // 排队或评测中
+	VerdictFrozen              Verdict = "FROZEN"  // 封榜后隐藏的结果
+)
+
+// 评测结果的各种写法，键为大写并将空格和短横线替换为下划线后的写法
+var verdictAliases = map[string]Verdict{
+	"ACCEPTED": VerdictAccepted,
+	"ACCEPT":   VerdictAccepted,
+	"AC":       VerdictAccepted,
+	"OK":       VerdictAccepted,
+	"CORRECT":  VerdictAccepted,
+	"YES":      VerdictAccepted,
+
+	"WRONG_ANSWER": VerdictWrongAnswer,
+	"WRONG":        VerdictWrongAnswer,
+	"WA":           VerdictWrongAnswer,
+	"INCORRECT":    VerdictWrongAnswer,
+
+	"TIME_LIMIT_EXCEEDED":     VerdictTimeLimitExceeded,
+	"TIME_LIMIT":              VerdictTimeLimitExceeded,
+	"TIMELIMIT":               VerdictTimeLimitExceeded,
+	"TIME_LIMIT_EXCEED":       VerdictTimeLimitExceeded,
+	"TLE":                     VerdictTimeLimitExceeded,
+	"TL":                      VerdictTimeLimitExceeded,
+	"TIMEOUT":                 VerdictTimeLimitExceeded,
+	"IDLENESS_LIMIT_EXCEEDED": VerdictTimeLimitExceeded,
+
+	"MEMORY_LIMIT_EXCEEDED": VerdictMemoryLimitExceeded,
+	"MEMORY_LIMIT":          VerdictMemoryLimitExceeded,
+	"MEMORY_LIMIT_EXCEED":   VerdictMemoryLimitExceeded,
+	"MLE":                   VerdictMemoryLimitExceeded,
+	"ML":                    VerdictMemoryLimitExceeded,
+
+	"OUTPUT_LIMIT_EXCEEDED": VerdictOutputLimitExceeded,
+	"OUTPUT_LIMIT":          VerdictOutputLimitExceeded,
+	"OUTPUT_LIMIT_EXCEED":   VerdictOutputLimitExceeded,
+	"OLE":                   VerdictOutputLimitExceeded,
+	"OL":                    VerdictOutputLimitExceeded,
+
+	"RUNTIME_ERROR":      VerdictRuntimeError,
+	"RUN_TIME_ERROR":     VerdictRuntimeError,
+	"RUN_ERROR":          VerdictRuntimeError,
+	"RUNERROR":           VerdictRuntimeError,
+	"RE":                 VerdictRuntimeError,
+	"RTE":                VerdictRuntimeError,
+	"SEGMENTATION_FAULT": VerdictRuntimeError,
+
+	"PRESENTATION_ERROR": VerdictPresentationError,
+	"PRESENTATION":       VerdictPresentationError,
+	"PE":                 VerdictPresentationError,
+
+	"NO_OUTPUT": VerdictNoOutput,
+	"NO":        VerdictNoOutput,
+
+	"COMPILATION_ERROR":  VerdictCompilationError,
+	"COMPILE_ERROR":      VerdictCompilationError,
+	"COMPILER_ERROR":     VerdictCompilationError,
+	"COMPILATION_FAILED": VerdictCompilationError,
+	"CE":                 VerdictCompilationError,
+
+	"SECURITY_VIOLATION":  VerdictSecurityViolation,
+	"SECURITY_VIOLATED":   VerdictSecurityViolation,
+	"RESTRICTED_FUNCTION": VerdictSecurityViolation,
+	"SV":                  VerdictSecurityViolation,
+	"RF":                  VerdictSecurityViolation,
+
+	"SYSTEM_ERROR":    VerdictSystemError,
+	"JUDGEMENT_ERROR": VerdictSystemError,
+	"JUDGE_ERROR":     VerdictSystemError,
+	"INTERNAL_ERROR":  VerdictSystemError,
+	"SE":              VerdictSystemError,
+	"JE":              VerdictSystemError,
+	"IE":              VerdictSystemError,
+	"FAILED":          VerdictSystemError,
+	"CRASHED":         VerdictSystemError,
+
+	"PENDING":   VerdictPending,
+	"PD":        VerdictPending,
+	"QUEUING":   VerdictPending,
+	"QUEUED":    VerdictPending,
+	"WAITING":   VerdictPending,
+	"COMPILING": VerdictPending,
+	"JUDGING":   VerdictPending,
+	"RUNNING":   VerdictPending,
+	"TESTING":   VerdictPending,
+	"REJUDGING": VerdictPending,
+
+	"FROZEN": VerdictFrozen,
+}
+
+// NormalizeVerdict 将各种写法的评测结果转换为规范的评测结果
+//
+// 空字符串视为评测中，无法识别的写法转换为大写后原样保留，排名时视为错误的提交
+func NormalizeVerdict(status string) Verdict {
+	key := verdictKey(status)
+	if key == "" {
+		return VerdictPending
+	}
+	if verdict, ok := verdictAliases[key]; ok {
+		return verdict
+	}
+	return Verdict(key)
+}
+
+// verdictKey 返回评测结果写法的查找键
+func verdictKey(status string) string {
+	key := strings.ToUpper(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+}
+
+// IsPending 判断是否尚未出结果
+func (v Verdict) IsPending() bool {
+	return v == VerdictPending || v == VerdictFrozen
+}
+
+// IsAccepted 判断是否通过
+func (v Verdict) IsAccepted() bool {
+	return v == VerdictAccepted
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/lllllan02/scoreboardv2/pkg/files"
+)
+
+// RunVerdict 修改提交评测结果的请求
+type RunVerdict struct {
+	SubmissionId string `json:"submission_id"`       // 提交 id
+	Status       string `json:"status"`              // 新的评测结果
+	Timestamp    *int   `json:"timestamp,omitempty"` // 新结果生效的相对时间(毫秒)，默认为当前比赛时间，比赛结束后为结束时刻
+}
+
+// 比赛数据的写锁：比赛目录 -> 互斥锁
+var contestLocks sync.Map
+
+// lockContest 锁定比赛数据的写入，返回解锁函数
+func lockContest(path string) func() {
+	value, _ := contestLocks.LoadOrStore(contestDir(path), &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// adminPath 规范化写入的比赛路径，避免写到数据目录之外
+func adminPath(p string) (string, error) {
+	cleaned := path.Clean("/" + p)
+	if cleaned == "/" {
+		return "", errors.ErrEmptyContestPath
+	}
+	return cleaned, nil
+}
+
+// saved 写入比赛数据后清除缓存并通知实时模式的订阅者
+func saved(path string) {
+	invalidateContest(path)
+	NotifyContest(path)
+}
+
+// AddContestRuns 添加提交记录
+//
+// 提交 id 不能与已有提交重复，未指定时自动编号；返回添加后的提交
+func AddContestRuns(p string, runs []model.Run) ([]model.Run, error) {
+	path, err := adminPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := lockContest(path)
+	defer unlock()
+
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+	runList, err := loadRunForWrite(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 已有的提交 id
+	ids := make(map[string]bool, len(runList)+len(runs))
+	for _, run := range runList {
+		ids[run.SubmissionId] = true
+	}
+
+	added := make([]model.Run, 0, len(runs))
+	for i, run := range runs {
+		if err := validateRun(config, teamList, run); err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("第 %d 条提交: %s", i+1, err))
+		}
+
+		run.Status = config.NormalizeVerdict(string(run.Status))
+		if run.SubmissionId == "" {
+			run.SubmissionId = nextSubmissionId(ids, len(runList)+len(added)+1)
+		} else if ids[run.SubmissionId] {
+			return nil, errors.NewBadRequest(fmt.Sprintf("第 %d 条提交: 提交 %s 已存在", i+1, run.SubmissionId))
+		}
+		ids[run.SubmissionId] = true
+		added = append(added, run)
+	}
+
+	runList = append(runList, added...)
+	sort.SliceStable(runList, func(i, j int) bool {
+		return runList[i].Timestamp < runList[j].Timestamp
+	})
+	if err := files.Save(contestFile(path, "run.json"), runList); err != nil {
+		return nil, errors.NewInternalError("保存提交数据失败", err)
+	}
+	saved(path)
+
+	return added, nil
+}
+
+// UpdateContestRun 重测提交，旧的评测结果保留在评测历史中
+func UpdateContestRun(p string, verdict RunVerdict) (*model.Run, error) {
+	path, err := adminPath(p)
+	if err != nil {
+		return nil, err
+	}
+	if verdict.SubmissionId == "" || verdict.Status == "" {
+		return nil, errors.NewBadRequest("提交 id 和评测结果不能为空")
+	}
+
+	unlock := lockContest(path)
+	defer unlock()
+
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	runList, err := loadRunForWrite(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range runList {
+		if runList[i].SubmissionId != verdict.SubmissionId {
+			continue
+		}
+
+		// 新结果生效的时间
+		at := contestNow(config)
+		if verdict.Timestamp != nil {
+			if err := validateTimestamp(config, *verdict.Timestamp); err != nil {
+				return nil, errors.NewBadRequest(fmt.Sprintf("生效时间%s", err))
+			}
+			at = *verdict.Timestamp
+		}
+		runList[i].Rejudge(config.NormalizeVerdict(verdict.Status), max(at, runList[i].Timestamp))
+		if err := files.Save(contestFile(path, "run.json"), runList); err != nil {
+			return nil, errors.NewInternalError("保存提交数据失败", err)
+		}
+		saved(path)
+
+		return &runList[i], nil
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("提交 %s 不存在", verdict.SubmissionId))
+}
+
+// PutContestTeams 替换比赛的队伍数据
+func PutContestTeams(p string, teamList model.TeamList) (model.TeamList, error) {
+	path, err := adminPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, team := range teamList {
+		// 未指定队伍 id 时使用键
+		if team.TeamId == "" {
+			team.TeamId = model.FlexString(key)
+		}
+		if string(team.TeamId) != key {
+			return nil, errors.NewBadRequest(fmt.Sprintf("队伍 %s 的 team_id 与键不一致", key))
+		}
+		if team.Name == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("队伍 %s 缺少名称", key))
+		}
+		teamList[key] = team
+	}
+
+	unlock := lockContest(path)
+	defer unlock()
+
+	if err := files.Save(contestFile(path, "team.json"), teamList); err != nil {
+		return nil, errors.NewInternalError("保存队伍数据失败", err)
+	}
+	saved(path)
+
+	return teamList, nil
+}
+
+// PutContestConfig 替换比赛配置，比赛不存在时创建
+func PutContestConfig(p string, config *model.ContestConfig) (*model.ContestConfig, error) {
+	path, err := adminPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.ProblemQuantity <= 0:
+		return nil, errors.NewBadRequest("题目数量必须大于 0")
+	case len(config.ProblemId) > config.ProblemQuantity:
+		return nil, errors.NewBadRequest("题目编号数量超过题目数量")
+	case config.EndTime < config.StartTime:
+		return nil, errors.NewBadRequest("结束时间早于开始时间")
+	case config.Penalty < 0 || config.FrozenTime < 0:
+		return nil, errors.NewBadRequest("罚时和封榜时长不能为负数")
+	case config.FrozenTime > 0 && int64(config.FrozenTime) > config.EndTime-config.StartTime:
+		return nil, errors.NewBadRequest("封榜时长超过比赛时长")
+	}
+
+	unlock := lockContest(path)
+	defer unlock()
+
+	if err := files.Save(contestFile(path, "config.json"), config); err != nil {
+		return nil, errors.NewInternalError("保存比赛配置失败", err)
+	}
+	saved(path)
+
+	return config, nil
+}
+
+// contestNow 返回当前的比赛时间(毫秒)，不超过比赛结束时刻
+func contestNow(config *model.ContestConfig) int {
+	now := int(time.Now().Unix()-config.StartTime) * 1000
+	return max(min(now, config.Duration()), 0)
+}
+
+// loadRunForWrite 直接读取 run.json 并合并重测记录，提交数据不存在时返回空列表
+//
+// 不经过缓存，也不依赖比赛配置；文件损坏时返回错误，避免覆盖已有的提交数据
+func loadRunForWrite(path string) (model.RunList, error) {
+	filePath := contestFile(path, "run.json")
+	if _, err := os.Stat(filePath); stderrors.Is(err, fs.ErrNotExist) {
+		return make(model.RunList, 0), nil
+	}
+
+	var runList model.RunList
+	if err := files.Load(filePath, &runList); err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("%s 读取失败", filepath.Base(filePath)), err)
+	}
+
+	runList = runList.Merge()
+	sort.SliceStable(runList, func(i, j int) bool {
+		return runList[i].Timestamp < runList[j].Timestamp
+	})
+	return runList, nil
+}
+
+// validateRun 校验提交是否有效
+func validateRun(config *model.ContestConfig, teamList model.TeamList, run model.Run) error {
+	if _, ok := teamList[string(run.TeamId)]; !ok {
+		return fmt.Errorf("队伍 %s 不存在", run.TeamId)
+	}
+	if run.ProblemId < 0 || run.ProblemId >= config.ProblemQuantity {
+		return fmt.Errorf("题目索引 %d 超出范围", run.ProblemId)
+	}
+	if err := validateTimestamp(config, run.Timestamp); err != nil {
+		return fmt.Errorf("提交时间%s", err)
+	}
+	for _, judgement := range run.History {
+		if err := validateTimestamp(config, judgement.Timestamp); err != nil {
+			return fmt.Errorf("评测历史的时间%s", err)
+		}
+	}
+	if run.Status == "" {
+		return fmt.Errorf("评测结果不能为空")
+	}
+	return nil
+}
+
+// validateTimestamp 校验相对时间(毫秒)不为负数，配置了比赛时间时不超过比赛时长
+func validateTimestamp(config *model.ContestConfig, timestamp int) error {
+	if timestamp < 0 {
+		return fmt.Errorf("不能为负数")
+	}
+	if duration := config.Duration(); duration > 0 && timestamp > duration {
+		return fmt.Errorf("超过比赛时长")
+	}
+	return nil
+}
+
+// nextSubmissionId 返回未被使用的提交 id，从 start 开始递增
+func nextSubmissionId(ids map[string]bool, start int) string {
+	for id := start; ; id++ {
+		if !ids[strconv.Itoa(id)] {
+			return strconv.Itoa(id)
+		}
+	}
+}
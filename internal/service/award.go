@@ -0,0 +1,261 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// 奖牌类型
+const (
+	MedalGold   = "gold"
+	MedalSilver = "silver"
+	MedalBronze = "bronze"
+)
+
+// 奖牌名称
+var medalCitations = map[string]string{
+	MedalGold:   "金奖",
+	MedalSilver: "银奖",
+	MedalBronze: "铜奖",
+}
+
+// 预设奖牌比例，按正式队伍的 10%/20%/30% 计算
+var presetMedal = model.OfficialMedal{
+	GoldRatio:   0.1,
+	SilverRatio: 0.2,
+	BronzeRatio: 0.3,
+}
+
+// Award 奖项
+type Award struct {
+	Id       string `json:"id"`       // 奖项 id，参照 CLICS awards，如 gold-medal、first-to-solve-A
+	Citation string `json:"citation"` // 奖项名称
+}
+
+// medalLine 各奖牌的数量
+type medalLine struct {
+	Gold   int
+	Silver int
+	Bronze int
+}
+
+// teamAward 队伍获得的奖项
+type teamAward struct {
+	medal  string   // 正式队伍奖牌
+	awards []*Award // 所有奖项
+}
+
+// medalRules 返回需要颁发奖牌的组别及其规则，正式队伍排在最前
+func medalRules(config *model.ContestConfig) (groups []string, rules map[string]model.OfficialMedal) {
+	rules = make(map[string]model.OfficialMedal)
+
+	official := config.Medal.Official
+	if official.IsZero() && config.Medal.Type != "" {
+		official = presetMedal
+	}
+	if !official.IsZero() {
+		groups = append(groups, model.GroupOfficial)
+		rules[model.GroupOfficial] = official
+	}
+
+	others := make([]string, 0, len(config.Medal.Group))
+	for group, rule := range config.Medal.Group {
+		if group != model.GroupOfficial && !rule.IsZero() {
+			others = append(others, group)
+			rules[group] = rule
+		}
+	}
+	sort.Strings(others)
+
+	return append(groups, others...), rules
+}
+
+// medalLines 根据排行榜计算各组别的奖牌数量
+func medalLines(config *model.ContestConfig, teamList model.TeamList, rank *Rank) map[string]medalLine {
+	groups, rules := medalRules(config)
+
+	lines := make(map[string]medalLine, len(groups))
+	for _, group := range groups {
+		rule := rules[group]
+
+		// 统计比例基数
+		total := 0
+		for _, team := range teamList {
+			if medalEligible(team, group) {
+				total++
+			}
+		}
+		if rule.Base == model.MedalBaseSolved {
+			total = 0
+			for _, row := range medalRows(teamList, rank, group) {
+				if row.Solved > 0 {
+					total++
+				}
+			}
+		}
+
+		lines[group] = medalLine{
+			Gold:   medalCount(rule.Gold, rule.GoldRatio, total, rule.Rounding),
+			Silver: medalCount(rule.Silver, rule.SilverRatio, total, rule.Rounding),
+			Bronze: medalCount(rule.Bronze, rule.BronzeRatio, total, rule.Rounding),
+		}
+	}
+
+	return lines
+}
+
+// medalCount 计算奖牌数量，配置了固定数量时直接使用
+func medalCount(count int, ratio float64, total int, rounding string) int {
+	if count > 0 {
+		return count
+	}
+
+	value := ratio * float64(total)
+	switch rounding {
+	case model.RoundingFloor:
+		return int(math.Floor(value + 1e-9))
+	case model.RoundingRound:
+		return int(math.Round(value))
+	default:
+		// 减去一个极小值，避免浮点误差导致向上取整多算一个
+		return int(math.Ceil(value - 1e-9))
+	}
+}
+
+// computeAwards 根据完整排行榜计算每支队伍获得的奖项
+func computeAwards(config *model.ContestConfig, teamList model.TeamList, rank *Rank) map[string]*teamAward {
+	result := make(map[string]*teamAward)
+	add := func(teamId string, award *Award) {
+		if result[teamId] == nil {
+			result[teamId] = &teamAward{}
+		}
+		result[teamId].awards = append(result[teamId].awards, award)
+	}
+
+	// 各组别奖牌
+	groups, _ := medalRules(config)
+	lines := medalLines(config, teamList, rank)
+	lastGold := make([]*Row, 0)
+	for _, group := range groups {
+		rows := medalRows(teamList, rank, group)
+		for i, row := range rows {
+			medal := medalOf(lines[group], groupPlace(rows, i), row.Solved)
+			if medal == "" {
+				continue
+			}
+
+			if group == model.GroupOfficial {
+				add(row.TeamId, &Award{Id: medal + "-medal", Citation: medalCitations[medal]})
+				result[row.TeamId].medal = medal
+
+				// 记录最后一名金奖
+				if medal == MedalGold {
+					if len(lastGold) > 0 && lastGold[0].Place != row.Place {
+						lastGold = lastGold[:0]
+					}
+					lastGold = append(lastGold, row)
+				}
+				continue
+			}
+
+			add(row.TeamId, &Award{
+				Id:       group + "-" + medal + "-medal",
+				Citation: groupName(config, group) + medalCitations[medal],
+			})
+		}
+	}
+
+	special := config.Medal.Special
+
+	// 一血奖
+	if special.FirstToSolve {
+		for _, row := range rank.Rows {
+			for index, problem := range row.Problems {
+				if problem.FirstSolved {
+					label := string(rune('A' + index))
+					add(row.TeamId, &Award{Id: "first-to-solve-" + label, Citation: label + " 题一血"})
+				}
+			}
+		}
+	}
+
+	// 金牌末位奖
+	if special.LastGold {
+		for _, row := range lastGold {
+			add(row.TeamId, &Award{Id: "last-gold", Citation: "金牌末位"})
+		}
+	}
+
+	// 优胜奖
+	if special.HonorableMention {
+		for _, row := range medalRows(teamList, rank, model.GroupOfficial) {
+			if row.Solved > 0 && (result[row.TeamId] == nil || result[row.TeamId].medal == "") {
+				add(row.TeamId, &Award{Id: "honorable-mention", Citation: "优胜奖"})
+			}
+		}
+	}
+
+	return result
+}
+
+// attachAwards 将奖项添加到排行榜中
+func attachAwards(rank *Rank, awards map[string]*teamAward) {
+	for _, row := range rank.Rows {
+		if award, ok := awards[row.TeamId]; ok {
+			row.Medal = award.medal
+			row.Awards = award.awards
+		}
+	}
+}
+
+// medalOf 根据组内排名计算奖牌，未通过题目的队伍不颁发奖牌
+func medalOf(line medalLine, place int, solved int) string {
+	switch {
+	case solved == 0:
+		return ""
+	case place <= line.Gold:
+		return MedalGold
+	case place <= line.Gold+line.Silver:
+		return MedalSilver
+	case place <= line.Gold+line.Silver+line.Bronze:
+		return MedalBronze
+	default:
+		return ""
+	}
+}
+
+// medalRows 返回排行榜中可以获得组别奖牌的队伍
+func medalRows(teamList model.TeamList, rank *Rank, group string) []*Row {
+	teams := make(map[string]model.Team, len(teamList))
+	for _, team := range teamList {
+		teams[string(team.TeamId)] = team
+	}
+
+	rows := make([]*Row, 0)
+	for _, row := range rank.Rows {
+		if medalEligible(teams[row.TeamId], group) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// medalEligible 判断队伍能否获得组别奖牌，打星队伍不参与评奖
+func medalEligible(team model.Team, group string) bool {
+	if team.IsUnofficial() {
+		return false
+	}
+	return group == model.GroupOfficial || groupFilter(team, group)
+}
+
+// groupPlace 返回第 index 支队伍在组内的排名，成绩相同的队伍排名相同
+func groupPlace(rows []*Row, index int) int {
+	for index > 0 &&
+		rows[index-1].Solved == rows[index].Solved &&
+		rows[index-1].Penalty == rows[index].Penalty {
+		index--
+	}
+	return index + 1
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/config"
+	"github.com/lllllan02/scoreboardv2/pkg/lru"
+)
+
+// 比赛数据缓存，按比赛路径下的文件缓存解析后的数据
+var contestCache = lru.New[string, *cacheEntry](config.GetConfig().Cache.Capacity)
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	stamps []fileStamp // 加载时依赖文件的状态
+	value  any         // 解析后的数据
+}
+
+// fileStamp 文件状态，用于判断文件是否被修改
+type fileStamp struct {
+	path    string
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// cached 从缓存中读取数据，缓存不存在或依赖的文件被修改时重新加载
+func cached[T any](key string, files []string, load func() (T, error)) (T, error) {
+	stamps := statFiles(files)
+
+	if entry, ok := contestCache.Get(key); ok && sameStamps(entry.stamps, stamps) {
+		return entry.value.(T), nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	contestCache.Add(key, &cacheEntry{stamps: stamps, value: value})
+	return value, nil
+}
+
+// invalidateContest 清除比赛的所有缓存数据
+func invalidateContest(path string) {
+	dir := contestDir(path)
+	contestCache.RemoveFunc(func(key string) bool {
+		return strings.HasPrefix(key, dir+string(filepath.Separator)) || strings.HasPrefix(key, dir+"#")
+	})
+}
+
+// contestDir 返回比赛数据目录
+func contestDir(path string) string {
+	return filepath.Join(dataPath, path)
+}
+
+// contestFile 返回比赛数据目录下的文件路径
+func contestFile(path string, name string) string {
+	return filepath.Join(dataPath, path, name)
+}
+
+// statFiles 获取文件状态
+func statFiles(files []string) []fileStamp {
+	stamps := make([]fileStamp, 0, len(files))
+	for _, file := range files {
+		stamp := fileStamp{path: file}
+		if info, err := os.Stat(file); err == nil {
+			stamp.exists = true
+			stamp.size = info.Size()
+			stamp.modTime = info.ModTime()
+		}
+		stamps = append(stamps, stamp)
+	}
+	return stamps
+}
+
+// sameStamps 判断文件状态是否一致
+func sameStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].path != b[i].path ||
+			a[i].exists != b[i].exists ||
+			a[i].size != b[i].size ||
+			!a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,538 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/internal/clics"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/lllllan02/scoreboardv2/pkg/slices"
+)
+
+// CLICS Contest API 端点
+const (
+	CLICSContest        = ""
+	CLICSState          = "state"
+	CLICSJudgementTypes = "judgement-types"
+	CLICSLanguages      = "languages"
+	CLICSProblems       = "problems"
+	CLICSGroups         = "groups"
+	CLICSOrganizations  = "organizations"
+	CLICSTeams          = "teams"
+	CLICSSubmissions    = "submissions"
+	CLICSJudgements     = "judgements"
+	CLICSScoreboard     = "scoreboard"
+	CLICSEventFeed      = "event-feed"
+)
+
+// 支持的 CLICS 端点
+var clicsEndpoints = []string{
+	CLICSState, CLICSJudgementTypes, CLICSLanguages, CLICSProblems, CLICSGroups,
+	CLICSOrganizations, CLICSTeams, CLICSSubmissions, CLICSJudgements, CLICSScoreboard, CLICSEventFeed,
+}
+
+// SplitCLICSPath 将请求路径拆分为比赛路径和端点，如 /icpc/2024/nanjing/teams
+func SplitCLICSPath(path string) (contestPath string, endpoint string) {
+	path = strings.TrimSuffix(path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		for _, e := range clicsEndpoints {
+			if path[i+1:] == e {
+				return path[:i], e
+			}
+		}
+	}
+	return path, CLICSContest
+}
+
+// clicsContestId 将比赛路径转换为不含斜杠的 CLICS 比赛 id，如 /icpc/2024/nanjing -> icpc-2024-nanjing
+func clicsContestId(path string) string {
+	return strings.ReplaceAll(strings.Trim(path, "/"), "/", "-")
+}
+
+// clicsContestPath 将 CLICS 比赛 id 还原为比赛路径，已经是比赛路径时原样返回
+//
+// 目录名本身可能包含 -，因此逐级在数据目录中查找存在的子目录
+func clicsContestPath(path string) string {
+	id := strings.Trim(path, "/")
+	if id == "" || strings.Contains(id, "/") {
+		return path
+	}
+	if _, err := os.Stat(contestFile(id, "config.json")); err == nil {
+		return path
+	}
+	if dir, ok := findContestDir("", strings.Split(id, "-")); ok {
+		return "/" + dir
+	}
+	return path
+}
+
+// findContestDir 在 dir 下查找由 parts 以 / 或 - 连接而成的比赛目录
+func findContestDir(dir string, parts []string) (string, bool) {
+	if len(parts) == 0 {
+		_, err := os.Stat(contestFile(dir, "config.json"))
+		return dir, err == nil
+	}
+	for i := 1; i <= len(parts); i++ {
+		sub := path.Join(dir, strings.Join(parts[:i], "-"))
+		if info, err := os.Stat(contestDir(sub)); err != nil || !info.IsDir() {
+			continue
+		}
+		if found, ok := findContestDir(sub, parts[i:]); ok {
+			return found, true
+		}
+	}
+	return "", false
+}
+
+// clicsContest 生成 CLICS 数据所需的比赛数据
+type clicsContest struct {
+	path     string
+	config   *model.ContestConfig
+	teamList model.TeamList
+	runList  model.RunList
+	hiddenAt int               // 隐藏评测结果的起始时间(毫秒)，-1 表示不隐藏
+	orgIds   map[string]string // 组织名称 -> 组织 id
+}
+
+// GetCLICS 返回 CLICS Contest API 端点的数据
+//
+// 封榜后的评测结果默认不公开，query.Unfrozen 为 true 时返回真实结果
+func GetCLICS(path string, endpoint string, query BoardQuery) (any, error) {
+	c, err := loadCLICSContest(path, query.Unfrozen)
+	if err != nil {
+		return nil, err
+	}
+
+	switch endpoint {
+	case CLICSContest:
+		return c.contest(), nil
+	case CLICSState:
+		return c.state(), nil
+	case CLICSJudgementTypes:
+		return c.judgementTypes(), nil
+	case CLICSLanguages:
+		return c.languages(), nil
+	case CLICSProblems:
+		return c.problems(), nil
+	case CLICSGroups:
+		return c.groups(), nil
+	case CLICSOrganizations:
+		return c.organizations(), nil
+	case CLICSTeams:
+		return c.teams(), nil
+	case CLICSSubmissions:
+		return c.submissions(), nil
+	case CLICSJudgements:
+		return c.judgements(), nil
+	case CLICSScoreboard:
+		return c.scoreboard(query)
+	default:
+		return nil, errors.NewNotFound("不支持的 CLICS 端点")
+	}
+}
+
+// GetCLICSEventFeed 返回比赛的 event-feed 事件
+//
+// 事件按 2022-07 格式生成，提交和评测按时间顺序排列
+func GetCLICSEventFeed(path string, query BoardQuery) ([]*clics.Event, error) {
+	c, err := loadCLICSContest(path, query.Unfrozen)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*clics.Event, 0)
+	add := func(typ string, id string, data any) {
+		raw, _ := json.Marshal(data)
+		events = append(events, &clics.Event{Id: &id, Type: typ, Data: raw})
+	}
+
+	contest := c.contest()
+	add(clics.TypeContest, contest.Id, contest)
+	for _, item := range c.judgementTypes() {
+		add(clics.TypeJudgementTypes, item.Id, item)
+	}
+	for _, item := range c.languages() {
+		add(clics.TypeLanguages, item.Id, item)
+	}
+	for _, item := range c.problems() {
+		add(clics.TypeProblems, item.Id, item)
+	}
+	for _, item := range c.groups() {
+		add(clics.TypeGroups, item.Id, item)
+	}
+	for _, item := range c.organizations() {
+		add(clics.TypeOrganizations, item.Id, item)
+	}
+	for _, item := range c.teams() {
+		add(clics.TypeTeams, item.Id, item)
+	}
+
+	// 提交和评测按时间顺序排列，时间相同时提交在前
+	type timed struct {
+		time  int
+		typ   string
+		id    string
+		value any
+	}
+	items := make([]timed, 0)
+	for _, submission := range c.submissions() {
+		t, _ := clics.ParseRelTime(submission.ContestTime)
+		items = append(items, timed{t, clics.TypeSubmissions, submission.Id, submission})
+	}
+	for _, judgement := range c.judgements() {
+		t, _ := clics.ParseRelTime(judgement.StartContestTime)
+		items = append(items, timed{t, clics.TypeJudgements, judgement.Id, judgement})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].time < items[j].time
+	})
+	for _, item := range items {
+		add(item.typ, item.id, item.value)
+	}
+
+	add(clics.TypeState, contest.Id, c.state())
+
+	return events, nil
+}
+
+// loadCLICSContest 加载生成 CLICS 数据所需的比赛数据
+func loadCLICSContest(path string, unfrozen bool) (*clicsContest, error) {
+	path = clicsContestPath(path)
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+
+	runList, err := loadRun(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &clicsContest{
+		path:     path,
+		config:   config,
+		teamList: teamList,
+		runList:  runList,
+		hiddenAt: frozenAt(config, unfrozen),
+		orgIds:   make(map[string]string),
+	}
+
+	// 组织没有 id，按名称排序后依次编号
+	names := make([]string, 0, len(teamList))
+	for _, team := range teamList {
+		names = append(names, team.Organization)
+	}
+	names = slices.Unique(slices.RemoveEmpty(names))
+	sort.Strings(names)
+	for i, name := range names {
+		c.orgIds[name] = fmt.Sprintf("org-%d", i+1)
+	}
+
+	return c, nil
+}
+
+// absTime 返回相对时间(毫秒)对应的绝对时间
+func (c *clicsContest) absTime(ms int) string {
+	return clics.FormatTime(time.UnixMilli(c.config.StartTime*1000 + int64(ms)))
+}
+
+// contest 返回比赛信息
+func (c *clicsContest) contest() *clics.Contest {
+	penalty, _ := json.Marshal(c.config.PenaltySeconds() / 60)
+	contest := &clics.Contest{
+		Id:             clicsContestId(c.path),
+		Name:           c.config.ContestName,
+		FormalName:     c.config.ContestName,
+		Duration:       clics.FormatRelTime(c.config.Duration()),
+		PenaltyTime:    penalty,
+		ScoreboardType: "pass-fail",
+	}
+
+	if c.config.StartTime > 0 {
+		startTime := c.absTime(0)
+		contest.StartTime = &startTime
+	}
+	if c.config.FrozenTime > 0 {
+		freeze := clics.FormatRelTime(c.config.FrozenTime * 1000)
+		contest.ScoreboardFreezeDuration = &freeze
+	}
+
+	return contest
+}
+
+// state 返回比赛状态，根据当前时间判断比赛是否已开始、已结束
+func (c *clicsContest) state() *clics.State {
+	state := &clics.State{}
+	if c.config.StartTime == 0 {
+		return state
+	}
+
+	now := time.Now().Unix()
+	if now < c.config.StartTime {
+		return state
+	}
+	started := c.absTime(0)
+	state.Started = &started
+
+	if frozenAt := c.config.FrozenAt(); frozenAt >= 0 && int64(frozenAt) <= (now-c.config.StartTime)*1000 {
+		frozen := c.absTime(frozenAt)
+		state.Frozen = &frozen
+	}
+
+	if now < c.config.EndTime {
+		return state
+	}
+	ended := c.absTime(c.config.Duration())
+	state.Ended = &ended
+
+	// 封过榜的比赛在公开真实结果后才视为已解冻和最终结果
+	if state.Frozen != nil && c.hiddenAt < 0 {
+		state.Thawed = &ended
+	}
+	if state.Frozen == nil || state.Thawed != nil {
+		state.Finalized = &ended
+		state.EndOfUpdates = &ended
+	}
+
+	return state
+}
+
+// judgementTypes 返回评测结果类型，是否计罚时与比赛的罚时规则一致
+func (c *clicsContest) judgementTypes() []*clics.JudgementType {
+	rule := newPenaltyRule(c.config)
+	return clics.JudgementTypes(func(status string) bool {
+		return rule.penaltyFree(model.Verdict(status))
+	})
+}
+
+// languages 返回提交中出现过的编程语言
+func (c *clicsContest) languages() []*clics.Language {
+	names := make([]string, 0)
+	for _, run := range c.runList {
+		names = append(names, run.Language)
+	}
+	names = slices.Unique(slices.RemoveEmpty(names))
+	sort.Strings(names)
+
+	languages := make([]*clics.Language, 0, len(names))
+	for _, name := range names {
+		languages = append(languages, &clics.Language{Id: clicsId(name), Name: name})
+	}
+	return languages
+}
+
+// problems 返回题目列表
+func (c *clicsContest) problems() []*clics.Problem {
+	problems := make([]*clics.Problem, 0, c.config.ProblemQuantity)
+	for index := 0; index < c.config.ProblemQuantity; index++ {
+		label := c.config.ProblemLabel(index)
+		problem := &clics.Problem{Id: label, Label: label, Name: label, Ordinal: index}
+		if index < len(c.config.BalloonColor) {
+			problem.Rgb = c.config.BalloonColor[index].BackgroundColor
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// groups 返回队伍分组
+func (c *clicsContest) groups() []*clics.Group {
+	groups, _ := GetContestGroup(c.path)
+
+	result := make([]*clics.Group, 0, len(groups))
+	for _, group := range groups {
+		if group.Id != model.GroupAll {
+			result = append(result, &clics.Group{Id: group.Id, Name: group.Name})
+		}
+	}
+	return result
+}
+
+// organizations 返回组织列表
+func (c *clicsContest) organizations() []*clics.Organization {
+	organizations := make([]*clics.Organization, 0, len(c.orgIds))
+	for name, id := range c.orgIds {
+		organizations = append(organizations, &clics.Organization{Id: id, Name: name, FormalName: name})
+	}
+	sort.Slice(organizations, func(i, j int) bool {
+		return organizations[i].Name < organizations[j].Name
+	})
+	return organizations
+}
+
+// teams 返回队伍列表
+func (c *clicsContest) teams() []*clics.Team {
+	teams := make([]*clics.Team, 0, len(c.teamList))
+	for _, team := range c.teamList {
+		teams = append(teams, &clics.Team{
+			Id:             string(team.TeamId),
+			Name:           string(team.Name),
+			DisplayName:    string(team.Name),
+			OrganizationId: c.orgIds[team.Organization],
+			GroupIds:       teamGroups(c.config, team),
+		})
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Id < teams[j].Id
+	})
+	return teams
+}
+
+// submissions 返回提交列表
+func (c *clicsContest) submissions() []*clics.Submission {
+	submissions := make([]*clics.Submission, 0, len(c.runList))
+	for index, run := range c.runList {
+		submissions = append(submissions, &clics.Submission{
+			Id:          submissionId(run, index),
+			LanguageId:  clicsId(run.Language),
+			ProblemId:   c.config.ProblemLabel(run.ProblemId),
+			TeamId:      string(run.TeamId),
+			Time:        c.absTime(run.Timestamp),
+			ContestTime: clics.FormatRelTime(run.Timestamp),
+		})
+	}
+	return submissions
+}
+
+// judgements 返回评测列表，封榜后的提交和封榜后的重测不公开评测结果
+//
+// 重测过的提交每次评测对应一个评测，第一次评测的 id 与提交 id 相同，之后依次为 {提交 id}-2、{提交 id}-3
+func (c *clicsContest) judgements() []*clics.Judgement {
+	judgements := make([]*clics.Judgement, 0, len(c.runList))
+	for index, run := range c.runList {
+		if c.hiddenAt >= 0 && run.Timestamp >= c.hiddenAt {
+			continue
+		}
+
+		history := run.History
+		if len(history) == 0 {
+			history = []model.Judgement{{Status: run.Status, Timestamp: run.Timestamp}}
+		}
+
+		id := submissionId(run, index)
+		for k, item := range history {
+			if k > 0 && c.hiddenAt >= 0 && item.Timestamp >= c.hiddenAt {
+				break
+			}
+
+			judgementId := id
+			if k > 0 {
+				judgementId = fmt.Sprintf("%s-%d", id, k+1)
+			}
+
+			contestTime := clics.FormatRelTime(item.Timestamp)
+			judgement := &clics.Judgement{
+				Id:               judgementId,
+				SubmissionId:     id,
+				StartTime:        c.absTime(item.Timestamp),
+				StartContestTime: contestTime,
+			}
+
+			// 尚未评测完成的提交没有评测结果
+			if !item.Status.IsPending() {
+				judgementType := clics.JudgementTypeId(string(item.Status))
+				judgement.JudgementTypeId = &judgementType
+				judgement.EndTime = &judgement.StartTime
+				judgement.EndContestTime = &contestTime
+			}
+
+			judgements = append(judgements, judgement)
+		}
+	}
+	return judgements
+}
+
+// scoreboard 返回排行榜
+func (c *clicsContest) scoreboard(query BoardQuery) (*clics.Scoreboard, error) {
+	t := query.Time
+	if t <= 0 {
+		t = contestEnd(c.config)
+	}
+
+	// 排行榜时间不超过比赛结束时间
+	at := t
+	if duration := c.config.Duration(); at > duration {
+		at = duration
+	}
+
+	rank, err := GetContestRank(c.path, BoardQuery{Group: query.Group, Time: t, Unfrozen: query.Unfrozen})
+	if err != nil {
+		return nil, err
+	}
+
+	scoreboard := &clics.Scoreboard{
+		Time:        c.absTime(at),
+		ContestTime: clics.FormatRelTime(at),
+		State:       c.state(),
+		Rows:        make([]*clics.ScoreboardRow, 0, len(rank.Rows)),
+	}
+	for _, row := range rank.Rows {
+		scoreboardRow := &clics.ScoreboardRow{
+			Rank:     row.Place,
+			TeamId:   row.TeamId,
+			Score:    clics.Score{NumSolved: row.Solved, TotalTime: row.Penalty},
+			Problems: make([]*clics.ScoreboardProblem, 0),
+		}
+		// CLICS 的罚时以分钟为单位
+		if c.config.PenaltyCalculation() == model.PenaltyInSeconds {
+			scoreboardRow.Score.TotalTime /= 60
+		}
+		for index, problem := range row.Problems {
+			if problem.Submitted == 0 {
+				continue
+			}
+
+			scoreboardProblem := &clics.ScoreboardProblem{
+				ProblemId:    c.config.ProblemLabel(index),
+				NumJudged:    problem.Submitted - problem.PendingCount,
+				NumPending:   problem.PendingCount,
+				Solved:       problem.Solved,
+				FirstToSolve: problem.FirstSolved,
+			}
+			if problem.Solved {
+				scoreboardProblem.Time = problem.Timestamp
+			}
+			scoreboardRow.Problems = append(scoreboardRow.Problems, scoreboardProblem)
+		}
+		scoreboard.Rows = append(scoreboard.Rows, scoreboardRow)
+	}
+
+	return scoreboard, nil
+}
+
+// submissionId 返回提交 id，缺失时按提交顺序编号
+func submissionId(run model.Run, index int) string {
+	if run.SubmissionId != "" {
+		return run.SubmissionId
+	}
+	return fmt.Sprintf("%d", index+1)
+}
+
+// clicsId 将名称转换为 CLICS 允许的 id，只包含字母、数字、下划线、点和短横线
+func clicsId(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			builder.WriteRune(r)
+		case r == '+':
+			builder.WriteString("p")
+		case r == '#':
+			builder.WriteString("sharp")
+		default:
+			builder.WriteString("-")
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLICSContestPath(t *testing.T) {
+	dataPath = t.TempDir()
+	for _, dir := range []string{"icpc/2024/nanjing", "ccpc/2024-final"} {
+		if err := os.MkdirAll(filepath.Join(dataPath, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dataPath, dir, "config.json"), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want string
+		id   string
+	}{
+		{path: "/icpc/2024/nanjing", want: "/icpc/2024/nanjing", id: "icpc-2024-nanjing"},
+		{path: "/icpc-2024-nanjing", want: "/icpc/2024/nanjing", id: "icpc-2024-nanjing"},
+		{path: "/ccpc-2024-final", want: "/ccpc/2024-final", id: "ccpc-2024-final"},
+		{path: "/unknown-contest", want: "/unknown-contest", id: "unknown-contest"},
+	}
+	for _, tt := range tests {
+		got := clicsContestPath(tt.path)
+		if got != tt.want {
+			t.Errorf("clicsContestPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+		if id := clicsContestId(got); id != tt.id {
+			t.Errorf("clicsContestId(%q) = %q, want %q", got, id, tt.id)
+		}
+	}
+}
@@ -1,36 +1,47 @@
 package service
 
 import (
-	"math"
-	"strings"
-
 	"github.com/lllllan02/scoreboardv2/internal/model"
 )
 
-func GetContestConfig(path string) (*model.ContestConfig, error) {
+// ContestConfig 比赛配置，附带按最终排名计算的奖牌数量
+type ContestConfig struct {
+	*model.ContestConfig
+	MedalCounts map[string]model.OfficialMedal `json:"medal_counts,omitempty"` // 组别 -> 奖牌数量，只包含数量
+}
+
+// LoadContestConfig 返回比赛配置文件中的配置
+func LoadContestConfig(path string) (*model.ContestConfig, error) {
+	return loadConfig(path)
+}
+
+// GetContestConfig 返回比赛配置
+//
+// 配置了奖牌时，按比赛结束时的真实排名(封榜后的结果也计入)计算各组别的奖牌数量，
+// 与滚榜和奖项使用的数量一致；配置中的奖牌规则保持不变
+func GetContestConfig(path string) (*ContestConfig, error) {
 	config, err := loadConfig(path)
 	if err != nil {
 		return nil, err
 	}
+	result := &ContestConfig{ContestConfig: config}
 
-	// 如果奖牌类型不为空，则根据队伍数量计算奖牌数量
-	if strings.ToLower(config.Medal.Type) != "" {
+	if groups, _ := medalRules(config); len(groups) > 0 {
 		team, err := loadTeam(path)
 		if err != nil {
 			return nil, err
 		}
 
-		total := 0
-		for _, team := range team {
-			if team.Official {
-				total++
-			}
+		rank, err := GetContestRank(path, BoardQuery{Time: contestEnd(config), Unfrozen: true})
+		if err != nil {
+			return nil, err
 		}
 
-		config.Medal.Official.Gold = int(math.Ceil(float64(total) * 0.1))
-		config.Medal.Official.Silver = int(math.Ceil(float64(total) * 0.2))
-		config.Medal.Official.Bronze = int(math.Ceil(float64(total) * 0.3))
+		result.MedalCounts = make(map[string]model.OfficialMedal, len(groups))
+		for group, line := range medalLines(config, team, rank) {
+			result.MedalCounts[group] = model.OfficialMedal{Gold: line.Gold, Silver: line.Silver, Bronze: line.Bronze}
+		}
 	}
 
-	return config, nil
+	return result, nil
 }
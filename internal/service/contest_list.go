@@ -16,9 +16,15 @@ func GetContestList(contestName string) ([]*model.Contest, error) {
 	filePath := filepath.Join(dataPath, "contest_list.json")
 
 	// 加载比赛列表
-	var contestList model.ContestList
-	if err := files.Load(filePath, &contestList); err != nil {
-		return nil, errors.ErrContestListNotFound
+	contestList, err := cached(filePath, []string{filePath}, func() (model.ContestList, error) {
+		var contestList model.ContestList
+		if err := files.Load(filePath, &contestList); err != nil {
+			return contestList, errors.ErrContestListNotFound
+		}
+		return contestList, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 遍历比赛列表
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// 快照间隔(毫秒)，每隔一段比赛时间保存一次所有队伍的状态
+const snapshotInterval = 5 * 60 * 1000
+
+// cell 队伍在一道题目上的状态
+type cell struct {
+	Problem
+	solvedAt int // 通过的提交在提交序列中的下标，用于计算一血
+	penalty  int // 累计罚时(秒)
+}
+
+// boardState 某一时刻所有队伍的题目状态
+type boardState map[string][]cell // team_id -> 每道题目的状态
+
+// clone 复制状态
+func (s boardState) clone() boardState {
+	copied := make(boardState, len(s))
+	for teamId, cells := range s {
+		copied[teamId] = append([]cell(nil), cells...)
+	}
+	return copied
+}
+
+// snapshot 某一时刻的状态快照
+type snapshot struct {
+	time  int        // 快照时刻(毫秒)
+	index int        // 已处理的事件数量
+	state boardState // 队伍状态
+}
+
+// event 改变榜单状态的事件：提交或重测
+type event struct {
+	time    int  // 事件发生的相对时间(毫秒)
+	run     int  // 提交在提交序列中的下标
+	rejudge bool // 是否为重测
+}
+
+// rankEngine 增量排名引擎
+//
+// 提交和重测事件只处理一次，并在包含事件的间隔末尾保存快照，比赛结束后不再保存快照。
+// 查询任意时刻 t 的排名时，从最近的快照开始只处理之后的事件。
+type rankEngine struct {
+	problemQuantity int
+	rule            penaltyRule      // 罚时计算规则
+	frozenAt        int              // 封榜开始的相对时间(毫秒)，-1 表示不隐藏结果
+	runs            model.RunList    // 按时间排序的提交记录
+	events          []event          // 按时间排序的事件
+	cellRuns        map[string][]int // 被重测的题目(team_id#题目索引) -> 该题目的提交下标
+	submitEvents    []int            // 提交下标 -> 提交事件在事件序列中的下标
+	snapshots       []*snapshot      // 按时刻排序的快照
+}
+
+// loadRankEngine 加载比赛的排名引擎，unfrozen 为 true 时不隐藏封榜后的结果
+func loadRankEngine(path string, unfrozen bool) (*rankEngine, error) {
+	key := contestDir(path) + "#engine"
+	if unfrozen {
+		key += "-unfrozen"
+	}
+	deps := []string{contestFile(path, "config.json"), contestFile(path, "run.json")}
+
+	return cached(key, deps, func() (*rankEngine, error) {
+		config, err := loadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+
+		runList, err := loadRun(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRankEngine(config, runList, unfrozen), nil
+	})
+}
+
+// newRankEngine 创建排名引擎并生成快照
+func newRankEngine(config *model.ContestConfig, runs model.RunList, unfrozen bool) *rankEngine {
+	e := &rankEngine{
+		problemQuantity: config.ProblemQuantity,
+		rule:            newPenaltyRule(config),
+		frozenAt:        frozenAt(config, unfrozen),
+		runs:            runs,
+		events:          make([]event, 0, len(runs)),
+		cellRuns:        make(map[string][]int),
+	}
+
+	// 每个提交产生一个提交事件，每次重测产生一个重测事件
+	for index, run := range runs {
+		e.events = append(e.events, event{time: run.Timestamp, run: index})
+		if len(run.History) < 2 {
+			continue
+		}
+		for _, judgement := range run.History[1:] {
+			e.events = append(e.events, event{time: judgement.Timestamp, run: index, rejudge: true})
+		}
+		e.cellRuns[cellKey(string(run.TeamId), run.ProblemId)] = nil
+	}
+	sort.SliceStable(e.events, func(i, j int) bool {
+		return e.events[i].time < e.events[j].time
+	})
+
+	// 记录被重测的题目的所有提交及其提交事件的位置，重测时重新计算
+	if len(e.cellRuns) > 0 {
+		e.submitEvents = make([]int, len(runs))
+		for index, ev := range e.events {
+			if !ev.rejudge {
+				e.submitEvents[ev.run] = index
+			}
+		}
+		for index, run := range runs {
+			key := cellKey(string(run.TeamId), run.ProblemId)
+			if indexes, ok := e.cellRuns[key]; ok {
+				e.cellRuns[key] = append(indexes, index)
+			}
+		}
+	}
+
+	// 只在包含事件的间隔末尾保存快照，快照数量不超过事件数量，
+	// 比赛结束后的事件(如异常的时间戳)在查询时从最后一个快照开始处理
+	state := make(boardState)
+	end := contestEnd(config)
+	for index := 0; index < len(e.events) && e.events[index].time <= end; {
+		t := snapshotTime(e.events[index].time)
+		for ; index < len(e.events) && e.events[index].time <= t; index++ {
+			e.apply(state, index)
+		}
+		e.snapshots = append(e.snapshots, &snapshot{time: t, index: index, state: state.clone()})
+	}
+
+	return e
+}
+
+// snapshotTime 返回时刻 t 所在间隔的末尾，即不早于 t 的最近快照时刻
+func snapshotTime(t int) int {
+	if t <= 0 {
+		return 0
+	}
+	return (t + snapshotInterval - 1) / snapshotInterval * snapshotInterval
+}
+
+// stateAt 返回时刻 t 的状态
+func (e *rankEngine) stateAt(t int) boardState {
+	if t < 0 {
+		return make(boardState)
+	}
+
+	// 找到不晚于 t 的最近快照，没有时从头开始
+	state, start := make(boardState), 0
+	if k := sort.Search(len(e.snapshots), func(i int) bool { return e.snapshots[i].time > t }) - 1; k >= 0 {
+		state, start = e.snapshots[k].state.clone(), e.snapshots[k].index
+	}
+
+	// 处理快照之后的事件
+	for index := start; index < len(e.events) && e.events[index].time <= t; index++ {
+		e.apply(state, index)
+	}
+
+	return state
+}
+
+// apply 将第 index 个事件应用到状态上
+func (e *rankEngine) apply(state boardState, index int) {
+	ev := e.events[index]
+	if !ev.rejudge {
+		e.applyRun(state, ev.run, ev.time)
+		return
+	}
+
+	// 重测后按所有提交的新结果重新计算该题目的状态，
+	// 同一毫秒内排在重测之后的提交尚未处理，由其提交事件计入
+	run := e.runs[ev.run]
+	teamId := string(run.TeamId)
+	if cells, ok := state[teamId]; ok && run.ProblemId >= 0 && run.ProblemId < e.problemQuantity {
+		cells[run.ProblemId] = cell{}
+	}
+	for _, i := range e.cellRuns[cellKey(teamId, run.ProblemId)] {
+		if e.submitEvents[i] < index {
+			e.applyRun(state, i, ev.time)
+		}
+	}
+}
+
+// applyRun 将第 index 个提交在时刻 t 的评测结果应用到状态上
+func (e *rankEngine) applyRun(state boardState, index int, t int) {
+	run := e.runs[index]
+	teamId := string(run.TeamId)                       // 队伍 id
+	problemIndex := run.ProblemId                      // 题目索引
+	minute := run.Timestamp / 1000 / 60                // 提交时间(分钟)
+	status := run.StatusAt(visibleTime(t, e.frozenAt)) // 时刻 t 公开的评测结果
+
+	// 题目索引越界，则跳过
+	if problemIndex < 0 || problemIndex >= e.problemQuantity {
+		return
+	}
+
+	// 如果队伍不存在，则创建
+	if _, ok := state[teamId]; !ok {
+		state[teamId] = make([]cell, e.problemQuantity)
+	}
+	c := &state[teamId][problemIndex]
+
+	// 如果题目已经解决，则跳过
+	if c.Solved {
+		return
+	}
+
+	// 封榜后的提交隐藏评测结果，只累计提交次数
+	if e.frozenAt >= 0 && run.Timestamp >= e.frozenAt {
+		c.Frozen = true    // 设置为冻结
+		c.PendingCount++   // 未公布结果的提交次数加一
+		c.Attempted = true // 设置为尝试过
+		c.Submitted++      // 提交次数加一
+		return
+	}
+
+	// 尚未出结果的提交不计罚时，只累计提交次数
+	if status.IsPending() {
+		c.Pending = true   // 设置为评测中
+		c.PendingCount++   // 未公布结果的提交次数加一
+		c.Attempted = true // 设置为尝试过
+		c.Submitted++      // 提交次数加一
+		return
+	}
+
+	// 如果是不计罚时的提交(默认为编译错误)，则跳过
+	if e.rule.penaltyFree(status) {
+		return
+	}
+
+	if status.IsAccepted() {
+		c.Solved = true                             // 设置为已解决
+		c.penalty += e.rule.accepted(run.Timestamp) // 通过的提交加当前时间
+		c.Dirt = c.Submitted                        // 累计通过题目的错误次数
+		c.solvedAt = index                          // 记录通过的提交
+	} else {
+		c.penalty += e.rule.rejected() // 错误的提交加上配置的罚时
+	}
+
+	c.Penalty = e.rule.display(c.penalty) // 设置罚时
+	c.Timestamp = minute                  // 设置通过时间
+	c.Attempted = true                    // 设置为尝试过
+	c.Submitted++                         // 提交次数加一
+}
+
+// cellKey 返回队伍在一道题目上的键
+func cellKey(teamId string, problemIndex int) string {
+	return teamId + "#" + strconv.Itoa(problemIndex)
+}
+
+// frozenAt 返回需要隐藏结果的起始时间(毫秒)，-1 表示不隐藏
+func frozenAt(config *model.ContestConfig, unfrozen bool) int {
+	if unfrozen {
+		return -1
+	}
+	return config.FrozenAt()
+}
+
+// visibleTime 返回时刻 t 能看到的评测结果的截止时刻
+//
+// 封榜后的评测(包括封榜前提交的重测)不公开，hiddenAt 为 -1 时不隐藏
+func visibleTime(t int, hiddenAt int) int {
+	if hiddenAt >= 0 {
+		return min(t, hiddenAt-1)
+	}
+	return t
+}
+
+// contestEnd 返回比赛结束的相对时间(毫秒)，未配置比赛时间时包含所有提交
+func contestEnd(config *model.ContestConfig) int {
+	if duration := config.Duration(); duration > 0 {
+		return duration
+	}
+	return math.MaxInt
+}
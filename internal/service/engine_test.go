@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// rejudged 返回在时刻 at 重测为 status 的提交
+func rejudged(run model.Run, status model.Verdict, at int) model.Run {
+	run.Rejudge(status, at)
+	return run
+}
+
+func TestRankEngineFrozenRejudge(t *testing.T) {
+	// 比赛 1 小时，最后 10 分钟封榜
+	config := &model.ContestConfig{StartTime: 0, EndTime: 3600, FrozenTime: 600, ProblemQuantity: 1}
+	runs := model.RunList{
+		rejudged(model.Run{Status: model.VerdictWrongAnswer, TeamId: "1", Timestamp: 60000, SubmissionId: "1"}, model.VerdictAccepted, 3300000),
+	}
+
+	tests := []struct {
+		name     string
+		unfrozen bool
+		want     bool
+	}{
+		{name: "frozen", unfrozen: false, want: false},
+		{name: "unfrozen", unfrozen: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newRankEngine(config, runs, tt.unfrozen).stateAt(contestEnd(config))
+			if got := state["1"][0].Solved; got != tt.want {
+				t.Errorf("Solved = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrozenAtLongerThanContest(t *testing.T) {
+	config := &model.ContestConfig{StartTime: 0, EndTime: 3600, FrozenTime: 7200, ProblemQuantity: 1}
+	if got := config.FrozenAt(); got != 0 {
+		t.Errorf("FrozenAt() = %d, want 0", got)
+	}
+
+	runs := model.RunList{{Status: model.VerdictAccepted, TeamId: "1", Timestamp: 60000, SubmissionId: "1"}}
+	c := newRankEngine(config, runs, false).stateAt(contestEnd(config))["1"][0]
+	if c.Solved || !c.Frozen {
+		t.Errorf("cell = %+v, want frozen and unsolved", c)
+	}
+}
+
+func TestRankEngineRejudgeWithSubmissionInSameMillisecond(t *testing.T) {
+	config := &model.ContestConfig{StartTime: 0, EndTime: 3600, ProblemQuantity: 1}
+	// 第一个提交的重测与第二个提交发生在同一毫秒，重测事件排在提交事件之前
+	runs := model.RunList{
+		rejudged(model.Run{Status: model.VerdictAccepted, TeamId: "1", Timestamp: 60000, SubmissionId: "1"}, model.VerdictWrongAnswer, 120000),
+		{Status: model.VerdictWrongAnswer, TeamId: "1", Timestamp: 120000, SubmissionId: "2"},
+	}
+
+	e := newRankEngine(config, runs, true)
+	for _, at := range []int{120000, contestEnd(config)} {
+		c := e.stateAt(at)["1"][0]
+		if c.Solved || c.Submitted != 2 {
+			t.Errorf("stateAt(%d) = %+v, want unsolved with 2 submissions", at, c)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
-	"path/filepath"
+	"maps"
+	"os"
+	"slices"
 	"sort"
 
 	"github.com/lllllan02/scoreboardv2/config"
@@ -15,41 +17,96 @@ var dataPath = config.GetConfig().Data.Path
 
 // loadConfig 加载比赛配置
 func loadConfig(path string) (*model.ContestConfig, error) {
-	filePath := filepath.Join(dataPath, path, "config.json")
+	filePath := contestFile(path, "config.json")
 
-	var config model.ContestConfig
-	if err := files.Load(filePath, &config); err != nil {
-		return nil, errors.ErrContestConfigNotFound
+	config, err := cached(filePath, []string{filePath}, func() (*model.ContestConfig, error) {
+		var config model.ContestConfig
+		if err := files.Load(filePath, &config); err != nil {
+			return nil, errors.ErrContestConfigNotFound
+		}
+		return &config, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &config, nil
+	// 返回副本，避免调用方修改缓存数据
+	return cloneConfig(config), nil
+}
+
+// cloneConfig 深拷贝比赛配置，包括其中的 map 和切片
+func cloneConfig(config *model.ContestConfig) *model.ContestConfig {
+	copied := *config
+	copied.ProblemId = slices.Clone(config.ProblemId)
+	copied.Group = maps.Clone(config.Group)
+	copied.BalloonColor = slices.Clone(config.BalloonColor)
+	copied.Medal.Group = maps.Clone(config.Medal.Group)
+	copied.Options.PenaltyFreeStatus = slices.Clone(config.Options.PenaltyFreeStatus)
+	copied.Options.VerdictMapping = maps.Clone(config.Options.VerdictMapping)
+	return &copied
 }
 
 // loadTeam 加载队伍数据
+//
+// 返回的数据为缓存共享数据，调用方不应修改
 func loadTeam(path string) (model.TeamList, error) {
-	filePath := filepath.Join(dataPath, path, "team.json")
+	filePath := contestFile(path, "team.json")
 
-	var team model.TeamList
-	if err := files.Load(filePath, &team); err != nil {
-		return nil, errors.ErrContestTeamNotFound
-	}
+	return cached(filePath, []string{filePath}, func() (model.TeamList, error) {
+		var team model.TeamList
+		if err := files.Load(filePath, &team); err != nil {
+			return nil, errors.ErrContestTeamNotFound
+		}
+		return team, nil
+	})
+}
+
+// loadExternalIds 加载队伍 id 到外部 id(如 ICPC id)的对应关系
+//
+// 对应关系保存在 team.json 旁的 external_id.json 中，文件不存在时返回空的对应关系
+func loadExternalIds(path string) (map[string]string, error) {
+	filePath := contestFile(path, "external_id.json")
 
-	return team, nil
+	return cached(filePath, []string{filePath}, func() (map[string]string, error) {
+		ids := make(map[string]string)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return ids, nil
+		}
+		if err := files.Load(filePath, &ids); err != nil {
+			return nil, errors.NewInternalError("外部 id 数据读取失败", err)
+		}
+		return ids, nil
+	})
 }
 
 // loadRun 加载运行数据
+//
+// 返回的数据为缓存共享数据，调用方不应修改
 func loadRun(path string) (model.RunList, error) {
-	filePath := filepath.Join(dataPath, path, "run.json")
+	filePath := contestFile(path, "run.json")
+	deps := []string{filePath, contestFile(path, "config.json")}
 
-	var run model.RunList
-	if err := files.Load(filePath, &run); err != nil {
-		return nil, errors.ErrContestRunNotFound
-	}
+	return cached(filePath, deps, func() (model.RunList, error) {
+		var run model.RunList
+		if err := files.Load(filePath, &run); err != nil {
+			return nil, errors.ErrContestRunNotFound
+		}
 
-	// 将运行数据按时间排序
-	sort.Slice(run, func(i, j int) bool {
-		return run[i].Timestamp < run[j].Timestamp
-	})
+		// 按比赛配置规范化评测结果，缺少配置时使用默认的对应关系
+		config, err := loadConfig(path)
+		if err != nil {
+			config = &model.ContestConfig{}
+		}
+		run.Normalize(config)
 
-	return run, nil
+		// 同一提交的多条记录合并为重测
+		run = run.Merge()
+
+		// 将运行数据按时间排序
+		sort.SliceStable(run, func(i, j int) bool {
+			return run[i].Timestamp < run[j].Timestamp
+		})
+
+		return run, nil
+	})
 }
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// Group 组别信息
+type Group struct {
+	Id    string `json:"id"`    // 组别 id
+	Name  string `json:"name"`  // 组别名称
+	Count int    `json:"count"` // 队伍数量
+}
+
+// 内置组别，按展示顺序排列
+var builtinGroups = []string{
+	model.GroupOfficial,
+	model.GroupUnofficial,
+	model.GroupGirl,
+	model.GroupUndergraduate,
+	model.GroupVocational,
+}
+
+// 内置组别名称
+var builtinGroupNames = map[string]string{
+	model.GroupAll:           "所有队伍",
+	model.GroupOfficial:      "正式队伍",
+	model.GroupUnofficial:    "打星队伍",
+	model.GroupGirl:          "女队",
+	model.GroupUndergraduate: "本科组",
+	model.GroupVocational:    "高职组",
+}
+
+// GetContestGroup 返回比赛的组别列表及各组别的队伍数量
+//
+// 内置组别只返回有队伍的组别，比赛配置中声明的组别全部返回
+func GetContestGroup(path string) ([]*Group, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 统计各组别的队伍数量
+	count := make(map[string]int)
+	for _, team := range teamList {
+		for _, group := range teamGroups(config, team) {
+			count[group]++
+		}
+	}
+
+	groups := []*Group{{Id: model.GroupAll, Name: groupName(config, model.GroupAll), Count: len(teamList)}}
+	for _, group := range builtinGroups {
+		if _, declared := config.Group[group]; declared || count[group] > 0 {
+			groups = append(groups, &Group{Id: group, Name: groupName(config, group), Count: count[group]})
+		}
+	}
+
+	// 自定义组别按 id 排序
+	custom := make([]string, 0, len(config.Group))
+	for group := range config.Group {
+		if group != model.GroupAll && !slices.Contains(builtinGroups, group) {
+			custom = append(custom, group)
+		}
+	}
+	sort.Strings(custom)
+	for _, group := range custom {
+		groups = append(groups, &Group{Id: group, Name: groupName(config, group), Count: count[group]})
+	}
+
+	return groups, nil
+}
+
+// groupFilter 根据组别过滤队伍，内置组别兼容队伍的布尔字段，其余组别根据队伍的组别列表判断
+func groupFilter(team model.Team, group string) bool {
+	switch group {
+	case "", model.GroupAll:
+		return true
+	case model.GroupGirl:
+		return bool(team.Girl) || slices.Contains(team.Group, group)
+	case model.GroupOfficial:
+		return bool(team.Official) || slices.Contains(team.Group, group)
+	case model.GroupUnofficial:
+		return team.IsUnofficial()
+	case model.GroupUndergraduate:
+		return bool(team.Undergraduate) || slices.Contains(team.Group, group)
+	case model.GroupVocational:
+		return bool(team.Vocational) || slices.Contains(team.Group, group)
+	default:
+		return slices.Contains(team.Group, group)
+	}
+}
+
+// groupName 返回组别名称，优先使用比赛配置中的名称
+func groupName(config *model.ContestConfig, group string) string {
+	if name, ok := config.Group[group]; ok {
+		return name
+	}
+	if name, ok := builtinGroupNames[group]; ok {
+		return name
+	}
+	return group
+}
+
+// teamGroups 返回队伍所属的组别，包括内置组别和比赛配置中声明的组别
+func teamGroups(config *model.ContestConfig, team model.Team) []string {
+	groups := make([]string, 0)
+	for _, group := range builtinGroups {
+		if groupFilter(team, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	for group := range config.Group {
+		if group != model.GroupAll && !slices.Contains(builtinGroups, group) && groupFilter(team, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// attachGroupPlaces 根据完整排行榜计算队伍在所属各组别中的排名
+//
+// 组内排名与按该组别筛选时的排名一致
+func attachGroupPlaces(config *model.ContestConfig, teamList model.TeamList, full *Rank, rank *Rank) {
+	teams := make(map[string]model.Team, len(teamList))
+	for _, team := range teamList {
+		teams[string(team.TeamId)] = team
+	}
+
+	places := make(map[string]map[string]int) // team_id -> group -> place
+	prev := make(map[string]*Row)             // group -> 组内上一支队伍
+	count := make(map[string]int)             // group -> 组内队伍数量
+	for _, row := range full.Rows {
+		places[row.TeamId] = make(map[string]int)
+		for _, group := range teamGroups(config, teams[row.TeamId]) {
+			count[group]++
+
+			// 如果和组内上一支队伍解决题目数和罚时相同，则排名相同
+			place := count[group]
+			if p := prev[group]; p != nil && p.Solved == row.Solved && p.Penalty == row.Penalty {
+				place = places[p.TeamId][group]
+			}
+
+			places[row.TeamId][group] = place
+			prev[group] = row
+		}
+	}
+
+	for _, row := range rank.Rows {
+		row.GroupPlaces = places[row.TeamId]
+	}
+}
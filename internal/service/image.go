@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lllllan02/scoreboardv2/internal/assets"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// GetContestLogo 返回比赛 logo 的图片数据，没有 logo 时返回空
+//
+// 依次使用内置的预设 logo、配置中的 base64 数据、配置的文件路径和比赛目录下的 logo.png
+func GetContestLogo(contest string) ([]byte, error) {
+	config, err := loadConfig(contest)
+	if err != nil {
+		return nil, err
+	}
+
+	if image := assets.PresetLogo(config.Logo.Preset); image != nil {
+		return image, nil
+	}
+
+	if data := config.Logo.Base64; data != "" {
+		// 移除 data:image/png;base64, 前缀
+		if i := strings.Index(data, ","); i > 0 {
+			data = data[i+1:]
+		}
+		image, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, errors.NewInternalError("logo 数据解码失败", err)
+		}
+		if isImage(image) {
+			return image, nil
+		}
+	}
+
+	return readImage(contestImageFile(contest, config.Logo.Path), contestImageFile(contest, "logo.png"))
+}
+
+// GetContestBanner 返回比赛横幅的图片数据，没有横幅时返回空
+//
+// 依次使用配置的文件路径和比赛目录下的 banner.png
+func GetContestBanner(contest string) ([]byte, error) {
+	config, err := loadConfig(contest)
+	if err != nil {
+		return nil, err
+	}
+
+	return readImage(contestImageFile(contest, config.Banner.Path), contestImageFile(contest, "banner.png"))
+}
+
+// contestImageFile 返回配置的图片路径对应的比赛目录下的文件，不在比赛目录下时返回空
+//
+// 配置的路径为网页中使用的地址，可以是数据目录下的路径(如 data/icpc/2024/logo.png)，
+// 也可以是相对比赛目录的路径(如 logo.png)；网址、包含 .. 的路径和指向比赛目录外的符号链接会被忽略
+func contestImageFile(contest string, p string) string {
+	p = filepath.ToSlash(p)
+	if p == "" || strings.Contains(p, "://") || slices.Contains(strings.Split(p, "/"), "..") {
+		return ""
+	}
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+
+	dir := contestDir(contest)
+	candidates := []string{filepath.Join(dir, filepath.FromSlash(p))}
+	prefix := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(dataPath)), "/") + "/"
+	if rest, ok := strings.CutPrefix(p, prefix); ok {
+		candidates = append([]string{filepath.Join(dataPath, filepath.FromSlash(rest))}, candidates...)
+	}
+
+	// 解析符号链接后再判断是否在比赛目录下，避免通过链接读取目录外的文件
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return ""
+	}
+	for _, file := range candidates {
+		file, err := filepath.EvalSymlinks(file)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, file); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				return file
+			}
+		}
+	}
+	return ""
+}
+
+// readImage 读取第一个存在的图片文件，都不存在或不是图片时返回空
+func readImage(files ...string) ([]byte, error) {
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		image, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewInternalError("图片读取失败", err)
+		}
+		if isImage(image) {
+			return image, nil
+		}
+	}
+	return nil, nil
+}
+
+// isImage 根据文件内容判断是否为图片
+func isImage(data []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(data), "image/")
+}
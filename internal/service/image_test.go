@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lllllan02/scoreboardv2/internal/assets"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/files"
+)
+
+// 最小的 PNG 文件头，足以被识别为图片
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestGetContestLogo(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret.png")
+	if err := os.WriteFile(secret, pngHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		logo  model.Logo
+		files map[string][]byte // 比赛目录下的文件
+		links map[string]string // 比赛目录下的符号链接 -> 链接目标
+		want  []byte
+	}{
+		{name: "preset", logo: model.Logo{Preset: "ICPC"}, want: assets.PresetLogo("icpc")},
+		{name: "relative path", logo: model.Logo{Path: "images/logo.png"}, files: map[string][]byte{"images/logo.png": pngHeader}, want: pngHeader},
+		{name: "data path", logo: model.Logo{Path: "{data}/contest/images/logo.png"}, files: map[string][]byte{"images/logo.png": pngHeader}, want: pngHeader},
+		{name: "default file", logo: model.Logo{Path: "/assets/logo.png"}, files: map[string][]byte{"logo.png": pngHeader}, want: pngHeader},
+		{name: "outside contest", logo: model.Logo{Path: secret}},
+		{name: "parent directory", logo: model.Logo{Path: "../secret.png"}},
+		{name: "symlink outside contest", logo: model.Logo{Path: "logo.png"}, links: map[string]string{"logo.png": secret}},
+		{name: "symlinked directory outside contest", logo: model.Logo{Path: "images/secret.png"}, links: map[string]string{"images": filepath.Dir(secret)}},
+		{name: "symlink inside contest", logo: model.Logo{Path: "link.png"}, files: map[string][]byte{"images/logo.png": pngHeader}, links: map[string]string{"link.png": "images/logo.png"}, want: pngHeader},
+		{name: "not an image", logo: model.Logo{Path: "config.json"}},
+		{name: "url", logo: model.Logo{Path: "https://example.com/logo.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeContest(t, model.ContestConfig{ProblemQuantity: 1}, model.TeamList{}, model.RunList{})
+			for name, data := range tt.files {
+				file := filepath.Join(contestDir(path), name)
+				if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(file, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, target := range tt.links {
+				if err := os.Symlink(target, filepath.Join(contestDir(path), name)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// 配置的路径可以以数据目录开头
+			logo := tt.logo
+			if rest, ok := strings.CutPrefix(logo.Path, "{data}"); ok {
+				logo.Path = filepath.ToSlash(dataPath) + rest
+			}
+			config := model.ContestConfig{ProblemQuantity: 1, Logo: logo}
+			if err := files.Save(contestFile(path, "config.json"), config); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := GetContestLogo(path)
+			if err != nil {
+				t.Fatalf("GetContestLogo() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("GetContestLogo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lllllan02/scoreboardv2/config"
+)
+
+// RankDiff 两次排行榜之间的变化
+type RankDiff struct {
+	Rows        []*RowDiff    `json:"rows"`         // 新增或排名、成绩变化的队伍
+	Cells       []*CellDiff   `json:"cells"`        // 状态变化的题目
+	FirstSolves []*FirstSolve `json:"first_solves"` // 新产生的一血
+}
+
+// RowDiff 变化后的队伍
+type RowDiff struct {
+	*Row
+	FromPlace int `json:"from_place"` // 变化前的排名，新增的队伍为 0
+}
+
+// CellDiff 变化后的题目状态
+type CellDiff struct {
+	TeamId  string  `json:"team_id"` // 队伍 id
+	Index   int     `json:"index"`   // 题目索引
+	Problem Problem `json:"problem"` // 题目状态
+}
+
+// FirstSolve 一血
+type FirstSolve struct {
+	TeamId    string `json:"team_id"`   // 队伍 id
+	Team      string `json:"team"`      // 队伍名称
+	Index     int    `json:"index"`     // 题目索引
+	Timestamp int    `json:"timestamp"` // 通过时间(分钟)
+}
+
+// IsEmpty 判断是否没有变化
+func (d *RankDiff) IsEmpty() bool {
+	return len(d.Rows) == 0 && len(d.Cells) == 0 && len(d.FirstSolves) == 0
+}
+
+// GetLiveRank 返回实时模式的排行榜，即包含目前所有提交的排行榜
+func GetLiveRank(path string, query BoardQuery) (*Rank, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Time = contestEnd(config)
+	return GetContestRank(path, query)
+}
+
+// DiffRank 比较两次排行榜，返回变化的队伍、题目和新产生的一血
+func DiffRank(prev, next *Rank) *RankDiff {
+	diff := &RankDiff{
+		Rows:        make([]*RowDiff, 0),
+		Cells:       make([]*CellDiff, 0),
+		FirstSolves: make([]*FirstSolve, 0),
+	}
+
+	rows := make(map[string]*Row, len(prev.Rows))
+	for _, row := range prev.Rows {
+		rows[row.TeamId] = row
+	}
+
+	for _, row := range next.Rows {
+		old, ok := rows[row.TeamId]
+		if !ok {
+			old = &Row{Problems: make([]Problem, len(row.Problems))}
+		}
+
+		if !ok || old.Place != row.Place || old.Solved != row.Solved || old.Penalty != row.Penalty {
+			diff.Rows = append(diff.Rows, &RowDiff{Row: row, FromPlace: old.Place})
+		}
+
+		for index, problem := range row.Problems {
+			if index < len(old.Problems) && old.Problems[index] == problem {
+				continue
+			}
+			diff.Cells = append(diff.Cells, &CellDiff{TeamId: row.TeamId, Index: index, Problem: problem})
+
+			if problem.FirstSolved && (index >= len(old.Problems) || !old.Problems[index].FirstSolved) {
+				diff.FirstSolves = append(diff.FirstSolves, &FirstSolve{
+					TeamId:    row.TeamId,
+					Team:      row.Team,
+					Index:     index,
+					Timestamp: problem.Timestamp,
+				})
+			}
+		}
+	}
+
+	return diff
+}
+
+// 实时模式的订阅中心
+var live = &liveHub{watchers: make(map[string]*liveWatcher)}
+
+// liveHub 按比赛管理数据变化的订阅
+type liveHub struct {
+	mu       sync.Mutex
+	watchers map[string]*liveWatcher // 比赛目录 -> 监听器
+}
+
+// liveWatcher 监听一场比赛的数据文件，变化时通知所有订阅者
+type liveWatcher struct {
+	files       []string
+	subscribers map[chan struct{}]struct{}
+	notify      chan struct{} // 写入数据后的主动通知
+	stop        chan struct{}
+}
+
+// SubscribeContest 订阅比赛数据的变化
+//
+// 数据文件被修改或调用 NotifyContest 时，返回的通道会收到通知；调用 cancel 取消订阅
+func SubscribeContest(path string) (changes <-chan struct{}, cancel func()) {
+	key := contestDir(path)
+	ch := make(chan struct{}, 1)
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+
+	w, ok := live.watchers[key]
+	if !ok {
+		w = &liveWatcher{
+			files: []string{
+				contestFile(path, "config.json"),
+				contestFile(path, "team.json"),
+				contestFile(path, "run.json"),
+			},
+			subscribers: make(map[chan struct{}]struct{}),
+			notify:      make(chan struct{}, 1),
+			stop:        make(chan struct{}),
+		}
+		live.watchers[key] = w
+		go w.run()
+	}
+	w.subscribers[ch] = struct{}{}
+
+	cancel = func() {
+		live.mu.Lock()
+		defer live.mu.Unlock()
+
+		if _, ok := w.subscribers[ch]; !ok {
+			return
+		}
+		delete(w.subscribers, ch)
+
+		// 没有订阅者时停止监听
+		if len(w.subscribers) == 0 {
+			close(w.stop)
+			delete(live.watchers, key)
+		}
+	}
+	return ch, cancel
+}
+
+// NotifyContest 通知订阅者比赛数据已变化，不必等待下一次检查
+func NotifyContest(path string) {
+	live.mu.Lock()
+	defer live.mu.Unlock()
+
+	if w, ok := live.watchers[contestDir(path)]; ok {
+		signal(w.notify)
+	}
+}
+
+// run 定期检查数据文件，变化时通知订阅者
+func (w *liveWatcher) run() {
+	interval := config.GetConfig().Live.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	stamps := statFiles(w.files)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			current := statFiles(w.files)
+			if sameStamps(stamps, current) {
+				continue
+			}
+			stamps = current
+		case <-w.notify:
+			stamps = statFiles(w.files)
+		}
+
+		live.mu.Lock()
+		for ch := range w.subscribers {
+			signal(ch)
+		}
+		live.mu.Unlock()
+	}
+}
+
+// signal 发送通知，通道中已有未处理的通知时不再重复发送
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
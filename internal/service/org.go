@@ -0,0 +1,141 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+)
+
+// 学校排名方式
+const (
+	OrgPolicyBest = "best" // 按学校最好的队伍排名
+	OrgPolicyTop  = "top"  // 按学校排名前 k 的队伍的解题数之和与罚时之和排名
+)
+
+// 按前 k 支队伍排名时默认的队伍数量
+const defaultOrgTopK = 3
+
+// OrgQuery 学校排名查询参数
+type OrgQuery struct {
+	BoardQuery
+	Policy string `form:"policy"` // 排名方式：best/top，默认为 best
+	TopK   int    `form:"k"`      // 按前 k 支队伍排名时的队伍数量，默认为 3
+}
+
+// OrgRank 学校排行榜
+type OrgRank struct {
+	Policy string    `json:"policy"` // 排名方式
+	TopK   int       `json:"k"`      // 计入成绩的队伍数量，按最好的队伍排名时为 1
+	Rows   []*OrgRow `json:"rows"`   // 学校列表
+}
+
+// OrgRow 学校的成绩
+type OrgRow struct {
+	Place        int      `json:"place"`         // 学校排名
+	Organization string   `json:"organization"`  // 学校名称
+	Teams        int      `json:"teams"`         // 队伍数量
+	BestPlace    int      `json:"best_place"`    // 最好的队伍排名
+	BestTeamId   string   `json:"best_team_id"`  // 最好的队伍 id
+	BestTeam     string   `json:"best_team"`     // 最好的队伍名称
+	TotalSolved  int      `json:"total_solved"`  // 所有队伍的解题数之和
+	Solved       int      `json:"solved"`        // 计入成绩的队伍的解题数之和
+	Penalty      int      `json:"penalty"`       // 计入成绩的队伍的罚时之和
+	Gold         int      `json:"gold"`          // 金奖数量
+	Silver       int      `json:"silver"`        // 银奖数量
+	Bronze       int      `json:"bronze"`        // 铜奖数量
+	CountedTeams []string `json:"counted_teams"` // 计入成绩的队伍 id
+}
+
+// GetOrgRank 返回学校排行榜
+//
+// 学校的队伍按榜单排名排序，没有学校的队伍不参与学校排名。
+// 筛选组别和时刻与榜单一致，奖牌按完整榜单计算
+func GetOrgRank(path string, query OrgQuery) (*OrgRank, error) {
+	policy, k := query.Policy, query.TopK
+	switch policy {
+	case "", OrgPolicyBest:
+		policy, k = OrgPolicyBest, 1
+	case OrgPolicyTop:
+		if k < 0 {
+			return nil, errors.NewBadRequest("队伍数量不能为负数")
+		}
+		if k == 0 {
+			k = defaultOrgTopK
+		}
+	default:
+		return nil, errors.NewBadRequest("不支持的学校排名方式")
+	}
+
+	// 获取榜单
+	rank, err := GetContestRank(path, query.BoardQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按学校汇总，榜单已按排名排序
+	result := &OrgRank{Policy: policy, TopK: k, Rows: make([]*OrgRow, 0)}
+	orgs := make(map[string]*OrgRow)
+	for _, row := range rank.Rows {
+		if row.Organization == "" {
+			continue
+		}
+
+		org, ok := orgs[row.Organization]
+		if !ok {
+			org = &OrgRow{
+				Organization: row.Organization,
+				BestPlace:    row.Place,
+				BestTeamId:   row.TeamId,
+				BestTeam:     row.Team,
+				CountedTeams: make([]string, 0, k),
+			}
+			orgs[row.Organization] = org
+			result.Rows = append(result.Rows, org)
+		}
+
+		org.Teams++
+		org.TotalSolved += row.Solved
+		if org.Teams <= k {
+			org.Solved += row.Solved
+			org.Penalty += row.Penalty
+			org.CountedTeams = append(org.CountedTeams, row.TeamId)
+		}
+
+		switch row.Medal {
+		case MedalGold:
+			org.Gold++
+		case MedalSilver:
+			org.Silver++
+		case MedalBronze:
+			org.Bronze++
+		}
+	}
+
+	// 按计入成绩的解题数和罚时排序，相同时按最好的队伍排名排序
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		a, b := result.Rows[i], result.Rows[j]
+		if a.Solved != b.Solved {
+			return a.Solved > b.Solved
+		}
+		if a.Penalty != b.Penalty {
+			return a.Penalty < b.Penalty
+		}
+		return a.BestPlace < b.BestPlace
+	})
+
+	// 计算排名，成绩相同的学校排名相同
+	for i, row := range result.Rows {
+		if i > 0 && orgTied(result.Rows[i-1], row) {
+			row.Place = result.Rows[i-1].Place
+		} else {
+			row.Place = i + 1
+		}
+	}
+
+	return result, nil
+}
+
+// orgTied 判断两个学校的成绩是否相同
+func orgTied(a, b *OrgRow) bool {
+	return a.Solved == b.Solved && a.Penalty == b.Penalty && a.BestPlace == b.BestPlace
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// penaltyRule 罚时计算规则
+//
+// 罚时统一按秒累计，展示时根据计算方式转换为分钟或秒
+type penaltyRule struct {
+	calculation string          // 罚时计算方式
+	penalty     int             // 每次错误提交的罚时(秒)
+	freeStatus  []model.Verdict // 不计罚时的提交状态
+}
+
+// newPenaltyRule 根据比赛配置创建罚时计算规则
+func newPenaltyRule(config *model.ContestConfig) penaltyRule {
+	return penaltyRule{
+		calculation: config.PenaltyCalculation(),
+		penalty:     config.PenaltySeconds(),
+		freeStatus:  config.PenaltyFreeStatus(),
+	}
+}
+
+// penaltyFree 判断提交状态是否不计罚时，这类提交也不计入尝试次数
+func (r penaltyRule) penaltyFree(status model.Verdict) bool {
+	return slices.Contains(r.freeStatus, status)
+}
+
+// accepted 返回通过的提交计入的罚时(秒)，timestamp 为提交的相对时间(毫秒)
+func (r penaltyRule) accepted(timestamp int) int {
+	seconds := timestamp / 1000
+	if r.calculation == model.PenaltyInMinutes {
+		return seconds / 60 * 60
+	}
+	return seconds
+}
+
+// rejected 返回错误的提交计入的罚时(秒)
+func (r penaltyRule) rejected() int {
+	return r.penalty
+}
+
+// display 将累计罚时(秒)转换为展示的罚时
+//
+// in_seconds 按秒展示，其余计算方式按分钟展示
+func (r penaltyRule) display(seconds int) int {
+	if r.calculation == model.PenaltyInSeconds {
+		return seconds
+	}
+	return seconds / 60
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// ProblemAnalytics 题目的统计分析
+type ProblemAnalytics struct {
+	ProblemId       string          `json:"problem_id"`        // 题目编号
+	Submitted       int             `json:"submitted"`         // 提交次数
+	Attempted       int             `json:"attempted"`         // 尝试过的队伍数量
+	Solved          int             `json:"solved"`            // 解决的队伍数量
+	SolveRate       float64         `json:"solve_rate"`        // 解决的队伍占所有队伍的比例
+	FirstSolveTime  int             `json:"first_solve_time"`  // 一血的相对时间(毫秒)，没有队伍解决时为 -1
+	FirstSolveTeam  string          `json:"first_solve_team"`  // 一血的队伍 id
+	MedianSolveTime int             `json:"median_solve_time"` // 解决时间的中位数(毫秒)，没有队伍解决时为 -1
+	Cumulative      []*SolvePoint   `json:"cumulative"`        // 累计解决的队伍数量随时间的变化
+	Tries           []*TriesCount   `json:"tries"`             // 通过前的尝试次数分布
+	Languages       []*LanguageStat `json:"languages"`         // 各语言的提交情况
+	Groups          []*GroupRate    `json:"groups"`            // 各组别的解决情况
+}
+
+// SolvePoint 某一时刻累计解决的队伍数量
+type SolvePoint struct {
+	Time   int `json:"time"`   // 相对时间(毫秒)
+	Solved int `json:"solved"` // 累计解决的队伍数量
+}
+
+// TriesCount 在第 Tries 次尝试时通过的队伍数量
+type TriesCount struct {
+	Tries int `json:"tries"` // 尝试次数，包括通过的提交
+	Count int `json:"count"` // 队伍数量
+}
+
+// LanguageStat 一种语言的提交情况
+type LanguageStat struct {
+	Language  string `json:"language"`  // 语言
+	Submitted int    `json:"submitted"` // 提交次数
+	Accepted  int    `json:"accepted"`  // 通过的提交次数
+}
+
+// GroupRate 一个组别的解决情况
+type GroupRate struct {
+	Group     string  `json:"group"`      // 组别 id
+	Name      string  `json:"name"`       // 组别名称
+	Teams     int     `json:"teams"`      // 队伍数量
+	Solved    int     `json:"solved"`     // 解决的队伍数量
+	SolveRate float64 `json:"solve_rate"` // 解决率
+}
+
+// problemProgress 队伍在一道题目上的进度
+type problemProgress struct {
+	tries    int  // 已公布结果且计入尝试的提交次数
+	solved   bool // 是否已解决
+	solvedAt int  // 解决的提交时间(毫秒)
+}
+
+// GetProblemAnalytics 返回每道题目的统计分析
+//
+// 与比赛统计使用相同的筛选条件：只统计组别内的队伍在时刻 t 之前的提交。
+// 封榜后隐藏结果和评测中的提交只计入提交次数，不计罚时的提交不计入尝试次数
+func GetProblemAnalytics(path string, query StatQuery) ([]*ProblemAnalytics, error) {
+	group := query.Group
+
+	// 加载比赛配置
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	rule := newPenaltyRule(config)
+	hiddenAt := frozenAt(config, query.Unfrozen)
+
+	// 加载队伍列表
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+	teams := make(map[string]model.Team)
+	for _, team := range teamList {
+		if groupFilter(team, group) {
+			teams[string(team.TeamId)] = team
+		}
+	}
+
+	// 加载提交列表
+	runList, err := loadRun(path)
+	if err != nil {
+		return nil, err
+	}
+	t := statTime(config, runList, query.Time)
+
+	problems := make([]*ProblemAnalytics, config.ProblemQuantity)
+	progress := make([]map[string]*problemProgress, config.ProblemQuantity) // 题目 -> team_id -> 进度
+	languages := make([]map[string]*LanguageStat, config.ProblemQuantity)   // 题目 -> 语言 -> 提交情况
+	for i := range problems {
+		problems[i] = &ProblemAnalytics{
+			ProblemId:       config.ProblemLabel(i),
+			FirstSolveTime:  -1,
+			MedianSolveTime: -1,
+			Cumulative:      make([]*SolvePoint, 0),
+			Tries:           make([]*TriesCount, 0),
+			Languages:       make([]*LanguageStat, 0),
+			Groups:          make([]*GroupRate, 0),
+		}
+		progress[i] = make(map[string]*problemProgress)
+		languages[i] = make(map[string]*LanguageStat)
+	}
+
+	// 按提交时间统计
+	for _, run := range runList {
+		if run.Timestamp > t || run.ProblemId < 0 || run.ProblemId >= config.ProblemQuantity {
+			continue
+		}
+		teamId := string(run.TeamId)
+		if _, ok := teams[teamId]; !ok {
+			continue
+		}
+
+		problem := problems[run.ProblemId]
+		problem.Submitted++
+
+		language, ok := languages[run.ProblemId][run.Language]
+		if !ok {
+			language = &LanguageStat{Language: run.Language}
+			languages[run.ProblemId][run.Language] = language
+		}
+		language.Submitted++
+
+		p, ok := progress[run.ProblemId][teamId]
+		if !ok {
+			p = &problemProgress{}
+			progress[run.ProblemId][teamId] = p
+		}
+
+		// 隐藏结果、评测中和不计罚时的提交不影响解决情况
+		status := run.StatusAt(visibleTime(t, hiddenAt))
+		if (hiddenAt >= 0 && run.Timestamp >= hiddenAt) || status.IsPending() || rule.penaltyFree(status) {
+			continue
+		}
+		if status.IsAccepted() {
+			language.Accepted++
+		}
+		if p.solved {
+			continue
+		}
+
+		p.tries++
+		if status.IsAccepted() {
+			p.solved, p.solvedAt = true, run.Timestamp
+			if problem.FirstSolveTime == -1 {
+				problem.FirstSolveTime, problem.FirstSolveTeam = run.Timestamp, teamId
+			}
+		}
+	}
+
+	// 各组别的队伍数量
+	groups := analyticsGroups(config, teams)
+	groupTeams := make(map[string]int)
+	for _, team := range teams {
+		groupTeams[model.GroupAll]++
+		for _, g := range teamGroups(config, team) {
+			groupTeams[g]++
+		}
+	}
+
+	for i, problem := range problems {
+		problem.Attempted = len(progress[i])
+
+		var solveTimes []int
+		tries := make(map[int]int)
+		groupSolved := make(map[string]int)
+		for teamId, p := range progress[i] {
+			if !p.solved {
+				continue
+			}
+			solveTimes = append(solveTimes, p.solvedAt)
+			tries[p.tries]++
+
+			groupSolved[model.GroupAll]++
+			for _, g := range teamGroups(config, teams[teamId]) {
+				groupSolved[g]++
+			}
+		}
+		problem.Solved = len(solveTimes)
+		if len(teams) > 0 {
+			problem.SolveRate = float64(problem.Solved) / float64(len(teams))
+		}
+
+		// 解决时间的中位数和累计解决数量
+		sort.Ints(solveTimes)
+		if n := len(solveTimes); n > 0 {
+			problem.MedianSolveTime = (solveTimes[(n-1)/2] + solveTimes[n/2]) / 2
+		}
+		for j, solvedAt := range solveTimes {
+			if last := len(problem.Cumulative) - 1; last >= 0 && problem.Cumulative[last].Time == solvedAt {
+				problem.Cumulative[last].Solved = j + 1
+				continue
+			}
+			problem.Cumulative = append(problem.Cumulative, &SolvePoint{Time: solvedAt, Solved: j + 1})
+		}
+
+		// 尝试次数分布
+		for n, count := range tries {
+			problem.Tries = append(problem.Tries, &TriesCount{Tries: n, Count: count})
+		}
+		sort.Slice(problem.Tries, func(a, b int) bool {
+			return problem.Tries[a].Tries < problem.Tries[b].Tries
+		})
+
+		// 语言按提交次数排序
+		for _, language := range languages[i] {
+			problem.Languages = append(problem.Languages, language)
+		}
+		sort.Slice(problem.Languages, func(a, b int) bool {
+			if problem.Languages[a].Submitted != problem.Languages[b].Submitted {
+				return problem.Languages[a].Submitted > problem.Languages[b].Submitted
+			}
+			return problem.Languages[a].Language < problem.Languages[b].Language
+		})
+
+		// 各组别的解决率
+		for _, g := range groups {
+			rate := &GroupRate{Group: g, Name: groupName(config, g), Teams: groupTeams[g], Solved: groupSolved[g]}
+			if rate.Teams > 0 {
+				rate.SolveRate = float64(rate.Solved) / float64(rate.Teams)
+			}
+			problem.Groups = append(problem.Groups, rate)
+		}
+	}
+
+	return problems, nil
+}
+
+// analyticsGroups 返回队伍所属的组别，顺序与组别列表一致
+func analyticsGroups(config *model.ContestConfig, teams map[string]model.Team) []string {
+	present := make(map[string]bool)
+	for _, team := range teams {
+		for _, group := range teamGroups(config, team) {
+			present[group] = true
+		}
+	}
+
+	groups := []string{model.GroupAll}
+	for _, group := range builtinGroups {
+		if present[group] {
+			groups = append(groups, group)
+		}
+	}
+
+	custom := make([]string, 0)
+	for group := range present {
+		if !slices.Contains(builtinGroups, group) {
+			custom = append(custom, group)
+		}
+	}
+	sort.Strings(custom)
+
+	return append(groups, custom...)
+}
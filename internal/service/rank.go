@@ -1,12 +1,18 @@
 package service
 
 import (
-	"slices"
 	"sort"
 
 	"github.com/lllllan02/scoreboardv2/internal/model"
 )
 
+// BoardQuery 榜单查询参数
+type BoardQuery struct {
+	Group    string `form:"group"`    // 队伍组别
+	Time     int    `form:"t"`        // 相对时间(毫秒)
+	Unfrozen bool   `form:"unfrozen"` // 是否查看封榜后的真实结果
+}
+
 type Rank struct {
 	Rows        []*Row    `json:"rows"`         // 队伍列表
 	Submitted   []int     `json:"submitted"`    // 提交次数
@@ -19,32 +25,39 @@ type Rank struct {
 }
 
 type Row struct {
-	TeamId       string    `json:"team_id"`      // 队伍 id
-	Team         string    `json:"team"`         // 队伍名称
-	Organization string    `json:"organization"` // 队伍组织
-	Girl         bool      `json:"girl"`         // 是否是女队
-	Unofficial   bool      `json:"unofficial"`   // 是否是非正式队伍
-	Place        int       `json:"place"`        // 排名
-	OrgPlace     int       `json:"org_place"`    // 组织排名
-	Solved       int       `json:"solved"`       // 解决题目数
-	Penalty      int       `json:"penalty"`      // 罚时
-	Dirty        float64   `json:"dirty"`        // 错误率
-	Problems     []Problem `json:"problems"`     // 题目列表
+	TeamId       string         `json:"team_id"`          // 队伍 id
+	Team         string         `json:"team"`             // 队伍名称
+	Organization string         `json:"organization"`     // 队伍组织
+	Girl         bool           `json:"girl"`             // 是否是女队
+	Unofficial   bool           `json:"unofficial"`       // 是否是非正式队伍
+	Place        int            `json:"place"`            // 排名
+	OrgPlace     int            `json:"org_place"`        // 组织排名
+	Solved       int            `json:"solved"`           // 解决题目数
+	Penalty      int            `json:"penalty"`          // 罚时(in_seconds 为秒，其余为分钟)
+	Dirty        float64        `json:"dirty"`            // 错误率
+	Problems     []Problem      `json:"problems"`         // 题目列表
+	GroupPlaces  map[string]int `json:"group_places"`     // 所属各组别中的排名
+	Medal        string         `json:"medal,omitempty"`  // 正式队伍奖牌
+	Awards       []*Award       `json:"awards,omitempty"` // 获得的奖项
 }
 
 type Problem struct {
-	FirstSolved bool `json:"first_solved"` // 是否是第一个解决
-	Solved      bool `json:"solved"`       // 是否解决
-	Attempted   bool `json:"attempted"`    // 是否尝试
-	Pending     bool `json:"pending"`      // 正在评测
-	Frozen      bool `json:"frozen"`       // 是否冻结
-	Submitted   int  `json:"submitted"`    // 提交次数
-	Penalty     int  `json:"penalty"`      // 罚时(分钟)
-	Timestamp   int  `json:"timestamp"`    // 通过时间(分钟)
-	Dirt        int  `json:"dirt"`         // 错误次数(前提是已经解决)
+	FirstSolved  bool `json:"first_solved"`  // 是否是第一个解决
+	Solved       bool `json:"solved"`        // 是否解决
+	Attempted    bool `json:"attempted"`     // 是否尝试
+	Pending      bool `json:"pending"`       // 正在评测
+	Frozen       bool `json:"frozen"`        // 是否冻结
+	Submitted    int  `json:"submitted"`     // 提交次数
+	Penalty      int  `json:"penalty"`       // 罚时(in_seconds 为秒，其余为分钟)
+	Timestamp    int  `json:"timestamp"`     // 通过时间(分钟)
+	Dirt         int  `json:"dirt"`          // 错误次数(前提是已经解决)
+	PendingCount int  `json:"pending_count"` // 未公布结果的提交次数
 }
 
-func GetContestRank(path string, group string, t int) (*Rank, error) {
+// GetContestRank 返回比赛在时刻 t 的排行榜
+//
+// 默认隐藏封榜后提交的评测结果，query.Unfrozen 为 true 时返回真实排名
+func GetContestRank(path string, query BoardQuery) (*Rank, error) {
 	// 获取比赛配置
 	config, err := loadConfig(path)
 	if err != nil {
@@ -57,99 +70,100 @@ func GetContestRank(path string, group string, t int) (*Rank, error) {
 		return nil, err
 	}
 
+	// 获取排名引擎
+	engine, err := loadRankEngine(path, query.Unfrozen)
+	if err != nil {
+		return nil, err
+	}
+
+	state := engine.stateAt(query.Time)
+	rank := buildRank(config, teamList, state, query.Group)
+
+	// 奖项和组别排名按所有队伍的排名计算
+	full := rank
+	if query.Group != "" && query.Group != model.GroupAll {
+		full = buildRank(config, teamList, state, model.GroupAll)
+	}
+	attachAwards(rank, computeAwards(config, teamList, full))
+	attachGroupPlaces(config, teamList, full, rank)
+
+	return rank, nil
+}
+
+// buildRank 根据状态生成排行榜
+func buildRank(config *model.ContestConfig, teamList model.TeamList, state boardState, group string) *Rank {
+	problemQuantity := config.ProblemQuantity
+	rule := newPenaltyRule(config)
+
 	// 队伍 id 映射
 	rows := make(map[string]*Row)        // team_id -> row
 	teams := make(map[string]model.Team) // team_id -> team
 	for _, team := range teamList {
-		teamId := string(team.TeamId)
-		teams[teamId] = team
-	}
-
-	// 获取提交记录
-	runList, err := loadRun(path)
-	if err != nil {
-		return nil, err
+		teams[string(team.TeamId)] = team
 	}
 
 	// 创建排行榜结构
 	rank := &Rank{
 		Rows:        make([]*Row, 0, len(teamList)),
-		Submitted:   make([]int, config.ProblemQuantity),
-		Attempted:   make([]int, config.ProblemQuantity),
-		Accepted:    make([]int, config.ProblemQuantity),
-		Dirt:        make([]int, config.ProblemQuantity),
-		Dirty:       make([]float64, config.ProblemQuantity),
-		FirstSolved: make([]int, config.ProblemQuantity),
-		LastSolved:  make([]int, config.ProblemQuantity),
+		Submitted:   make([]int, problemQuantity),
+		Attempted:   make([]int, problemQuantity),
+		Accepted:    make([]int, problemQuantity),
+		Dirt:        make([]int, problemQuantity),
+		Dirty:       make([]float64, problemQuantity),
+		FirstSolved: make([]int, problemQuantity),
+		LastSolved:  make([]int, problemQuantity),
 	}
 
-	for i := 0; i < config.ProblemQuantity; i++ {
+	// 一血和最后一个通过的提交下标
+	first := make([]*Problem, problemQuantity)
+	firstAt := make([]int, problemQuantity)
+	lastAt := make([]int, problemQuantity)
+	for i := 0; i < problemQuantity; i++ {
 		rank.FirstSolved[i] = -1
+		firstAt[i] = -1
+		lastAt[i] = -1
 	}
 
-	// 遍历提交记录
-	for _, run := range runList {
-		teamId := string(run.TeamId)         // 队伍 id
-		problemIndex := run.ProblemId        // 题目索引
-		penalty := run.Timestamp / 1000 / 60 // 罚时
-
-		// 提交时间大于目标时间，则跳过
-		if run.Timestamp > t {
-			continue
-		}
-
+	// 有提交记录的队伍
+	for teamId, cells := range state {
 		// 如果筛选组别不符合，则跳过
 		team := teams[teamId]
 		if !groupFilter(team, group) {
 			continue
 		}
 
-		// 如果队伍不存在，则跳过
-		if _, ok := rows[teamId]; !ok {
-			rows[teamId] = &Row{
-				TeamId:       teamId,
-				Team:         string(team.Name),
-				Organization: string(team.Organization),
-				Girl:         bool(team.Girl),
-				Unofficial:   team.IsUnofficial(),
-				Problems:     make([]Problem, config.ProblemQuantity),
+		row := newRow(teamId, team, problemQuantity)
+		penalty := 0
+		for index, c := range cells {
+			row.Problems[index] = c.Problem
+			if !c.Solved {
+				continue
 			}
-		}
-		row := rows[teamId]
-
-		// 如果题目已经解决，则跳过
-		if row.Problems[problemIndex].Solved {
-			continue
-		}
-
-		// 如果编译错误，则跳过
-		if run.Status == "COMPILATION_ERROR" {
-			continue
-		}
-
-		if run.Status == "ACCEPTED" {
-			// 题目统计
-			row.Problems[problemIndex].Solved = true                               // 设置为已解决
-			row.Problems[problemIndex].Penalty += penalty                          // 通过的提交加当前时间
-			row.Problems[problemIndex].Dirt = row.Problems[problemIndex].Submitted // 累计通过题目的错误次数
 
 			// 队伍统计
-			row.Solved++                                      // 解决题目数加一
-			row.Penalty += row.Problems[problemIndex].Penalty // 累计通过题目的罚时
+			row.Solved++         // 解决题目数加一
+			penalty += c.penalty // 累计通过题目的罚时
 
 			// 排行榜统计
-			rank.LastSolved[problemIndex] = penalty // 设置为最后一个解决
-			if rank.FirstSolved[problemIndex] == -1 {
-				rank.FirstSolved[problemIndex] = penalty      // 设置为第一个解决
-				row.Problems[problemIndex].FirstSolved = true // 设置为第一个解决
+			if firstAt[index] == -1 || c.solvedAt < firstAt[index] {
+				firstAt[index] = c.solvedAt
+				first[index] = &row.Problems[index]
+				rank.FirstSolved[index] = c.Timestamp
+			}
+			if c.solvedAt > lastAt[index] {
+				lastAt[index] = c.solvedAt
+				rank.LastSolved[index] = c.Timestamp
 			}
-		} else {
-			row.Problems[problemIndex].Penalty += 20 // 错误的提交算 20 分钟罚时
 		}
+		row.Penalty = rule.display(penalty)
+		rows[teamId] = row
+	}
 
-		row.Problems[problemIndex].Timestamp = penalty // 设置通过时间
-		row.Problems[problemIndex].Attempted = true    // 设置为尝试过
-		row.Problems[problemIndex].Submitted++         // 提交次数加一
+	// 设置一血
+	for _, problem := range first {
+		if problem != nil {
+			problem.FirstSolved = true
+		}
 	}
 
 	// 将没提交过代码的队伍添加到排行榜
@@ -159,14 +173,7 @@ func GetContestRank(path string, group string, t int) (*Rank, error) {
 		}
 
 		if _, ok := rows[string(team.TeamId)]; !ok {
-			rows[string(team.TeamId)] = &Row{
-				TeamId:       string(team.TeamId),
-				Team:         string(team.Name),
-				Organization: string(team.Organization),
-				Girl:         bool(team.Girl),
-				Unofficial:   bool(team.Unofficial),
-				Problems:     make([]Problem, config.ProblemQuantity),
-			}
+			rows[string(team.TeamId)] = newRow(string(team.TeamId), team, problemQuantity)
 		}
 	}
 
@@ -200,7 +207,10 @@ func GetContestRank(path string, group string, t int) (*Rank, error) {
 		if rank.Rows[i].Solved != rank.Rows[j].Solved {
 			return rank.Rows[i].Solved > rank.Rows[j].Solved
 		}
-		return rank.Rows[i].Penalty < rank.Rows[j].Penalty
+		if rank.Rows[i].Penalty != rank.Rows[j].Penalty {
+			return rank.Rows[i].Penalty < rank.Rows[j].Penalty
+		}
+		return rank.Rows[i].TeamId < rank.Rows[j].TeamId
 	})
 
 	// 计算排名
@@ -222,23 +232,17 @@ func GetContestRank(path string, group string, t int) (*Rank, error) {
 		}
 	}
 
-	return rank, nil
+	return rank
 }
 
-// groupFilter 根据组别过滤队伍
-func groupFilter(team model.Team, group string) bool {
-	switch group {
-	case model.GroupGirl:
-		return bool(team.Girl)
-	case model.GroupOfficial:
-		return bool(team.Official) || slices.Contains(team.Group, group)
-	case model.GroupUnofficial:
-		return team.IsUnofficial()
-	case model.GroupUndergraduate:
-		return bool(team.Undergraduate) || slices.Contains(team.Group, group)
-	case model.GroupVocational:
-		return bool(team.Vocational) || slices.Contains(team.Group, group)
-	default:
-		return true
+// newRow 创建队伍行
+func newRow(teamId string, team model.Team, problemQuantity int) *Row {
+	return &Row{
+		TeamId:       teamId,
+		Team:         string(team.Name),
+		Organization: string(team.Organization),
+		Girl:         bool(team.Girl),
+		Unofficial:   team.IsUnofficial(),
+		Problems:     make([]Problem, problemQuantity),
 	}
 }
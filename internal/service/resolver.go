@@ -0,0 +1,248 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// ResolverQuery 滚榜查询参数
+type ResolverQuery struct {
+	Group string `form:"group"` // 队伍组别
+	Step  int    `form:"step"`  // 滚榜步骤，0 表示封榜时的状态
+}
+
+// Resolver 滚榜步骤列表
+type Resolver struct {
+	Total int             `json:"total"` // 步骤总数
+	Steps []*ResolverStep `json:"steps"` // 步骤列表
+}
+
+// ResolverStep 滚榜的一个步骤
+//
+// 揭晓步骤公布一支队伍一道冻结题目的结果，颁奖步骤在队伍排名确定后播报奖项
+type ResolverStep struct {
+	Index     int      `json:"index"`            // 步骤序号，从 1 开始
+	TeamId    string   `json:"team_id"`          // 队伍 id
+	Team      string   `json:"team"`             // 队伍名称
+	ProblemId string   `json:"problem_id"`       // 揭晓的题目，颁奖步骤为空
+	Solved    bool     `json:"solved"`           // 揭晓的题目是否通过
+	FromPlace int      `json:"from_place"`       // 揭晓前的排名
+	ToPlace   int      `json:"to_place"`         // 揭晓后的排名
+	Awards    []*Award `json:"awards,omitempty"` // 播报的奖项
+	Reveal    bool     `json:"reveal"`           // 是否为揭晓步骤
+
+	problem int // 揭晓的题目索引
+}
+
+// ResolverState 滚榜某一步骤的排行榜
+type ResolverState struct {
+	Step *ResolverStep `json:"step"` // 当前步骤，0 步时为空
+	Rank *Rank         `json:"rank"` // 当前排行榜
+}
+
+// resolver 滚榜过程
+type resolver struct {
+	frozen boardState      // 封榜时的状态
+	final  boardState      // 最终状态
+	steps  []*ResolverStep // 滚榜步骤
+}
+
+// resolverEntry 滚榜过程中的队伍排名信息
+type resolverEntry struct {
+	teamId  string
+	solved  int
+	penalty int
+}
+
+// less 判断队伍 a 是否排在队伍 b 前面
+func (a *resolverEntry) less(b *resolverEntry) bool {
+	if a.solved != b.solved {
+		return a.solved > b.solved
+	}
+	if a.penalty != b.penalty {
+		return a.penalty < b.penalty
+	}
+	return a.teamId < b.teamId
+}
+
+// GetContestResolver 返回比赛的滚榜步骤
+func GetContestResolver(path string, query ResolverQuery) (*Resolver, error) {
+	r, err := loadResolver(path, query.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{Total: len(r.steps), Steps: r.steps}, nil
+}
+
+// GetContestResolverState 返回滚榜到第 query.Step 步时的排行榜
+func GetContestResolverState(path string, query ResolverQuery) (*ResolverState, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := loadResolver(path, query.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	step := min(max(query.Step, 0), len(r.steps))
+	state := r.frozen.clone()
+	for _, s := range r.steps[:step] {
+		if s.Reveal {
+			state[s.TeamId][s.problem] = r.final[s.TeamId][s.problem]
+		}
+	}
+
+	result := &ResolverState{Rank: buildRank(config, teamList, state, query.Group)}
+	if step > 0 {
+		result.Step = r.steps[step-1]
+	}
+	return result, nil
+}
+
+// loadResolver 加载比赛的滚榜过程
+func loadResolver(path string, group string) (*resolver, error) {
+	key := contestDir(path) + "#resolver-" + group
+	deps := []string{contestFile(path, "config.json"), contestFile(path, "team.json"), contestFile(path, "run.json")}
+
+	return cached(key, deps, func() (*resolver, error) {
+		config, err := loadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+
+		teamList, err := loadTeam(path)
+		if err != nil {
+			return nil, err
+		}
+
+		frozenEngine, err := loadRankEngine(path, false)
+		if err != nil {
+			return nil, err
+		}
+
+		finalEngine, err := loadRankEngine(path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		// 以比赛结束时间为准
+		t := contestEnd(config)
+
+		return newResolver(config, teamList, frozenEngine.stateAt(t), finalEngine.stateAt(t), group), nil
+	})
+}
+
+// newResolver 从封榜状态开始模拟滚榜，生成滚榜步骤
+func newResolver(config *model.ContestConfig, teamList model.TeamList, frozen, final boardState, group string) *resolver {
+	r := &resolver{frozen: frozen, final: final}
+	rule := newPenaltyRule(config)
+
+	// 只保留符合组别的队伍
+	teams := make(map[string]model.Team)
+	for _, team := range teamList {
+		teams[string(team.TeamId)] = team
+	}
+	for teamId := range frozen {
+		if !groupFilter(teams[teamId], group) {
+			delete(frozen, teamId)
+		}
+	}
+
+	// 按封榜时的排名排序
+	state := frozen.clone()
+	entries := make([]*resolverEntry, 0, len(state))
+	for teamId, cells := range state {
+		entry := &resolverEntry{teamId: teamId}
+		entry.solved, entry.penalty = score(cells, rule)
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].less(entries[j]) })
+
+	// 最终排名对应的奖项
+	awards := computeAwards(config, teamList, buildRank(config, teamList, final, model.GroupAll))
+
+	// 从最后一名开始，依次揭晓排名最低的队伍的第一道冻结题目
+	for cursor := len(entries) - 1; cursor >= 0; {
+		entry := entries[cursor]
+		cells := state[entry.teamId]
+
+		problem := -1
+		for index, c := range cells {
+			if c.Frozen {
+				problem = index
+				break
+			}
+		}
+
+		// 队伍没有冻结的题目，排名确定，播报奖项
+		if problem == -1 {
+			if award, ok := awards[entry.teamId]; ok {
+				place := placeOf(entries, cursor)
+				r.steps = append(r.steps, &ResolverStep{
+					Index:     len(r.steps) + 1,
+					TeamId:    entry.teamId,
+					Team:      string(teams[entry.teamId].Name),
+					FromPlace: place,
+					ToPlace:   place,
+					Awards:    award.awards,
+				})
+			}
+			cursor--
+			continue
+		}
+
+		// 揭晓题目结果
+		fromPlace := placeOf(entries, cursor)
+		cells[problem] = final[entry.teamId][problem]
+		entry.solved, entry.penalty = score(cells, rule)
+
+		// 调整队伍位置
+		to := sort.Search(cursor, func(i int) bool { return entry.less(entries[i]) })
+		copy(entries[to+1:cursor+1], entries[to:cursor])
+		entries[to] = entry
+
+		r.steps = append(r.steps, &ResolverStep{
+			Index:     len(r.steps) + 1,
+			TeamId:    entry.teamId,
+			Team:      string(teams[entry.teamId].Name),
+			ProblemId: string(rune('A' + problem)),
+			problem:   problem,
+			Solved:    cells[problem].Solved,
+			FromPlace: fromPlace,
+			ToPlace:   placeOf(entries, to),
+			Reveal:    true,
+		})
+	}
+
+	return r
+}
+
+// placeOf 返回第 index 支队伍的排名，成绩相同的队伍排名相同
+func placeOf(entries []*resolverEntry, index int) int {
+	for index > 0 &&
+		entries[index-1].solved == entries[index].solved &&
+		entries[index-1].penalty == entries[index].penalty {
+		index--
+	}
+	return index + 1
+}
+
+// score 返回队伍的解题数和展示罚时
+func score(cells []cell, rule penaltyRule) (solved int, penalty int) {
+	for _, c := range cells {
+		if c.Solved {
+			solved++
+			penalty += c.penalty
+		}
+	}
+	return solved, rule.display(penalty)
+}
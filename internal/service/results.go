@@ -0,0 +1,190 @@
+package service
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/lllllan02/scoreboardv2/internal/clics"
+	"github.com/lllllan02/scoreboardv2/internal/model"
+)
+
+// ICPC 成绩中的奖项
+const (
+	ResultGoldMedal   = "Gold Medal"
+	ResultSilverMedal = "Silver Medal"
+	ResultBronzeMedal = "Bronze Medal"
+	ResultRanked      = "Ranked"
+	ResultHonorable   = "Honorable"
+)
+
+// 奖牌对应的 ICPC 奖项
+var resultMedals = map[string]string{
+	MedalGold:   ResultGoldMedal,
+	MedalSilver: ResultSilverMedal,
+	MedalBronze: ResultBronzeMedal,
+}
+
+// ResultsQuery 官方成绩查询参数
+type ResultsQuery struct {
+	Group string `form:"group"` // 队伍组别，默认为正式队伍
+	Time  *int   `form:"t"`     // 相对时间(毫秒)，默认为比赛结束
+}
+
+// Results ICPC 格式的比赛成绩
+type Results struct {
+	ProblemIds []string     `json:"problem_ids"` // 题目编号
+	Rows       []*ResultRow `json:"rows"`        // 按排名排序的队伍成绩
+}
+
+// ResultRow 一支队伍的成绩
+type ResultRow struct {
+	ExternalId   string    `json:"external_id"`  // 外部 id，未配置时为队伍 id
+	TeamId       string    `json:"team_id"`      // 队伍 id
+	Team         string    `json:"team"`         // 队伍名称
+	Organization string    `json:"organization"` // 队伍组织
+	Rank         int       `json:"rank"`         // 排名，没有解题的队伍为 0
+	Award        string    `json:"award"`        // 奖项：Gold Medal/Silver Medal/Bronze Medal/Ranked/Honorable
+	Solved       int       `json:"solved"`       // 解决题目数
+	Penalty      int       `json:"penalty"`      // 罚时(分钟)
+	LastSolved   int       `json:"last_solved"`  // 最后一次通过的时间(分钟)
+	GroupWinner  string    `json:"group_winner"` // 获得第一名的组别名称，只包含比赛配置中声明的自定义组别
+	Problems     []Problem `json:"problems"`     // 题目状态
+}
+
+// GetContestResults 返回 ICPC 格式的比赛成绩
+//
+// 未指定组别时只包含正式队伍，排名为组内排名，没有解题的队伍不排名；
+// 未指定时刻时为比赛结束时的成绩。官方成绩总是使用封榜后的真实结果，罚时按分钟计算
+func GetContestResults(path string, query ResultsQuery) (*Results, error) {
+	if query.Group == "" {
+		query.Group = model.GroupOfficial
+	}
+
+	// 获取比赛配置
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取榜单
+	rank, err := GetContestRank(path, resultsBoardQuery(config, query))
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取外部 id
+	externalIds, err := loadExternalIds(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 自定义组别
+	custom := make([]string, 0, len(config.Group))
+	for group := range config.Group {
+		if group != model.GroupAll && !slices.Contains(builtinGroups, group) {
+			custom = append(custom, group)
+		}
+	}
+	sort.Strings(custom)
+
+	results := &Results{Rows: make([]*ResultRow, 0, len(rank.Rows))}
+	for i := 0; i < config.ProblemQuantity; i++ {
+		results.ProblemIds = append(results.ProblemIds, config.ProblemLabel(i))
+	}
+
+	for _, row := range rank.Rows {
+		result := &ResultRow{
+			ExternalId:   externalId(externalIds, row.TeamId),
+			TeamId:       row.TeamId,
+			Team:         row.Team,
+			Organization: row.Organization,
+			Rank:         row.Place,
+			Award:        ResultRanked,
+			Solved:       row.Solved,
+			Penalty:      row.Penalty,
+			Problems:     row.Problems,
+		}
+		if config.PenaltyCalculation() == model.PenaltyInSeconds {
+			result.Penalty /= 60
+		}
+
+		if award, ok := resultMedals[row.Medal]; ok {
+			result.Award = award
+		} else if row.Solved == 0 {
+			result.Rank, result.Award = 0, ResultHonorable
+		}
+
+		for _, problem := range row.Problems {
+			if problem.Solved {
+				result.LastSolved = max(result.LastSolved, problem.Timestamp)
+			}
+		}
+
+		for _, group := range custom {
+			if row.Solved > 0 && row.GroupPlaces[group] == 1 {
+				result.GroupWinner = groupName(config, group)
+				break
+			}
+		}
+
+		results.Rows = append(results.Rows, result)
+	}
+
+	return results, nil
+}
+
+// GetContestAwards 返回 CLICS 格式的奖项，队伍 id 为外部 id
+//
+// 奖项按榜单中第一次出现的顺序排列，未指定时刻时为比赛结束时的奖项，总是使用封榜后的真实结果
+func GetContestAwards(path string, query ResultsQuery) ([]*clics.Award, error) {
+	// 获取比赛配置
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取榜单
+	rank, err := GetContestRank(path, resultsBoardQuery(config, query))
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取外部 id
+	externalIds, err := loadExternalIds(path)
+	if err != nil {
+		return nil, err
+	}
+
+	awards := make([]*clics.Award, 0)
+	index := make(map[string]*clics.Award)
+	for _, row := range rank.Rows {
+		for _, a := range row.Awards {
+			award, ok := index[a.Id]
+			if !ok {
+				award = &clics.Award{Id: a.Id, Citation: a.Citation, TeamIds: make([]string, 0, 1)}
+				index[a.Id] = award
+				awards = append(awards, award)
+			}
+			award.TeamIds = append(award.TeamIds, externalId(externalIds, row.TeamId))
+		}
+	}
+
+	return awards, nil
+}
+
+// resultsBoardQuery 返回官方成绩使用的榜单查询参数：封榜后的真实结果，默认为比赛结束时刻
+func resultsBoardQuery(config *model.ContestConfig, query ResultsQuery) BoardQuery {
+	t := contestEnd(config)
+	if query.Time != nil {
+		t = *query.Time
+	}
+	return BoardQuery{Group: query.Group, Time: t, Unfrozen: true}
+}
+
+// externalId 返回队伍的外部 id，未配置时为队伍 id
+func externalId(externalIds map[string]string, teamId string) string {
+	if id, ok := externalIds[teamId]; ok && id != "" {
+		return id
+	}
+	return teamId
+}
@@ -18,6 +18,7 @@ type ContestRunQuery struct {
 	Time     int    `form:"t"`
 	Page     int    `form:"page"`
 	PageSize int    `form:"page_size"`
+	Unfrozen bool   `form:"unfrozen"`
 }
 
 type ContestRun struct {
@@ -30,16 +31,18 @@ type ContestRun struct {
 }
 
 type Run struct {
-	Id           string `json:"id"`           // 提交 id
-	TeamId       string `json:"team_id"`      // 队伍 id
-	ProblemId    string `json:"problem_id"`   // 题目 id
-	Team         string `json:"team"`         // 队伍名称
-	Organization string `json:"organization"` // 队伍组织
-	Girl         bool   `json:"girl"`         // 是否是女队
-	Unofficial   bool   `json:"unofficial"`   // 是否是非正式队伍
-	Language     string `json:"language"`     // 语言
-	Status       string `json:"status"`       // 状态
-	Timestamp    int    `json:"timestamp"`    // 提交时间(相对时间，单位：毫秒)
+	Id           string            `json:"id"`                // 提交 id
+	TeamId       string            `json:"team_id"`           // 队伍 id
+	ProblemId    string            `json:"problem_id"`        // 题目 id
+	Team         string            `json:"team"`              // 队伍名称
+	Organization string            `json:"organization"`      // 队伍组织
+	Girl         bool              `json:"girl"`              // 是否是女队
+	Unofficial   bool              `json:"unofficial"`        // 是否是非正式队伍
+	Language     string            `json:"language"`          // 语言
+	Status       string            `json:"status"`            // 状态
+	Timestamp    int               `json:"timestamp"`         // 提交时间(相对时间，单位：毫秒)
+	Rejudged     bool              `json:"rejudged"`          // 是否被重测过
+	History      []model.Judgement `json:"history,omitempty"` // 重测过的提交的评测历史，依次为旧结果和新结果
 }
 
 type Participant struct {
@@ -48,7 +51,49 @@ type Participant struct {
 }
 
 // GetContestRun 返回比赛提交数据
-func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err error) {
+func GetContestRun(path string, query ContestRunQuery) (*ContestRun, error) {
+	result, err := filterContestRun(path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分页
+	start, end := paginate.Paginate(query.Page, query.PageSize, result.Total)
+	result.Data = result.Data[start:end]
+
+	return result, nil
+}
+
+// WalkContestRuns 按提交时间顺序依次处理符合筛选条件的提交，忽略分页参数
+//
+// 提交在处理时才转换为返回的格式，适合导出大量提交记录。fn 返回错误时停止处理并返回该错误
+func WalkContestRuns(path string, query ContestRunQuery, fn func(*Run) error) error {
+	filter, err := newRunFilter(path, query)
+	if err != nil {
+		return err
+	}
+
+	// 加载提交记录，已经按提交时间排序
+	runList, err := loadRun(path)
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runList {
+		run, ok := filter.visible(run)
+		if !ok || !filter.match(run) {
+			continue
+		}
+		if err := fn(filter.convert(run)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterContestRun 返回符合筛选条件的所有提交及筛选项
+func filterContestRun(path string, query ContestRunQuery) (result *ContestRun, err error) {
 	result = &ContestRun{
 		Total:        0,
 		Data:         make([]*Run, 0),
@@ -56,17 +101,14 @@ func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err
 		Participants: make([]*Participant, 0),
 	}
 
-	// 加载学校列表和参赛队伍
-	teamList, err := loadTeam(path)
+	filter, err := newRunFilter(path, query)
 	if err != nil {
 		return nil, err
 	}
 
-	schools := make(map[string]struct{})
-	teams := make(map[string]model.Team)
-	for _, team := range teamList {
-		schools[string(team.Organization)] = struct{}{}
-		teams[string(team.TeamId)] = team
+	// 学校列表
+	for _, team := range filter.teams {
+		result.Schools = append(result.Schools, string(team.Organization))
 	}
 
 	// 加载提交记录
@@ -77,19 +119,13 @@ func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err
 
 	participants := make(map[string]struct{})
 	for _, run := range runList {
-		teamId := string(run.TeamId)
-		team := teams[teamId]
-
-		// 如果筛选时间不符合，则跳过
-		if run.Timestamp > query.Time {
-			continue
-		}
-
-		// 如果筛选组别不符合，则跳过
-		if !groupFilter(team, query.Group) {
+		run, ok := filter.visible(run)
+		if !ok {
 			continue
 		}
 
+		teamId := string(run.TeamId)
+		team := filter.teams[teamId]
 		if _, ok := participants[teamId]; !ok {
 			participants[teamId] = struct{}{}
 			result.Participants = append(result.Participants, &Participant{
@@ -99,40 +135,11 @@ func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err
 		}
 		result.Schools = append(result.Schools, string(team.Organization))
 		result.Language = append(result.Language, run.Language)
-		result.Status = append(result.Status, run.Status)
-
-		// 如果筛选学校不符合，则跳过
-		if !schoolFilter(team, query.School) {
-			continue
-		}
-
-		// 如果筛选队伍不符合，则跳过
-		if !teamFilter(team, query.TeamId) {
-			continue
-		}
-
-		// 如果筛选语言不符合，则跳过
-		if !languageFilter(run, query.Language) {
-			continue
-		}
+		result.Status = append(result.Status, string(run.Status))
 
-		// 如果筛选状态不符合，则跳过
-		if !statusFilter(run, query.Status) {
-			continue
+		if filter.match(run) {
+			result.Data = append(result.Data, filter.convert(run))
 		}
-
-		result.Data = append(result.Data, &Run{
-			Id:           run.SubmissionId,
-			TeamId:       teamId,
-			ProblemId:    fmt.Sprintf("%c", run.ProblemId+65), // 转换为 A, B, C, D, E, F, G, H, I, J
-			Team:         string(team.Name),
-			Organization: string(team.Organization),
-			Girl:         bool(team.Girl),
-			Unofficial:   team.IsUnofficial(),
-			Language:     run.Language,
-			Status:       run.Status,
-			Timestamp:    run.Timestamp,
-		})
 	}
 
 	// 时间倒序
@@ -140,10 +147,7 @@ func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err
 		return result.Data[i].Timestamp > result.Data[j].Timestamp
 	})
 
-	// 分页
 	result.Total = len(result.Data)
-	start, end := paginate.Paginate(query.Page, query.PageSize, result.Total)
-	result.Data = result.Data[start:end]
 
 	// 去重
 	result.Language = slices.Unique(slices.RemoveEmpty(result.Language))
@@ -157,6 +161,89 @@ func GetContestRun(path string, query ContestRunQuery) (result *ContestRun, err
 	return result, nil
 }
 
+// runFilter 提交记录的筛选条件
+type runFilter struct {
+	config   *model.ContestConfig
+	query    ContestRunQuery
+	teams    map[string]model.Team // team_id -> 队伍
+	hiddenAt int                   // 隐藏评测结果的时刻
+	status   model.Verdict         // 筛选的评测结果
+}
+
+// newRunFilter 加载比赛配置和队伍，创建提交记录的筛选条件
+func newRunFilter(path string, query ContestRunQuery) (*runFilter, error) {
+	// 加载比赛配置
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 加载参赛队伍
+	teamList, err := loadTeam(path)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &runFilter{
+		config:   config,
+		query:    query,
+		teams:    make(map[string]model.Team, len(teamList)),
+		hiddenAt: frozenAt(config, query.Unfrozen),
+	}
+	for _, team := range teamList {
+		filter.teams[string(team.TeamId)] = team
+	}
+
+	// 筛选的评测结果支持各种写法，如 AC、WA
+	if query.Status != "" {
+		filter.status = config.NormalizeVerdict(query.Status)
+	}
+
+	return filter, nil
+}
+
+// visible 返回提交在筛选时刻展示的状态，以及提交是否在筛选时刻和组别内
+func (f *runFilter) visible(run model.Run) (model.Run, bool) {
+	// 时刻 t 公开的评测结果和评测历史，封榜后的重测不公开
+	run = runAt(run, visibleTime(f.query.Time, f.hiddenAt))
+
+	// 封榜后的提交隐藏评测结果
+	if f.hiddenAt >= 0 && run.Timestamp >= f.hiddenAt {
+		run.Status, run.History = model.VerdictFrozen, nil
+	}
+
+	// 筛选时间和组别
+	return run, run.Timestamp <= f.query.Time && groupFilter(f.teams[string(run.TeamId)], f.query.Group)
+}
+
+// match 判断提交是否符合学校、队伍、语言和状态的筛选条件
+func (f *runFilter) match(run model.Run) bool {
+	team := f.teams[string(run.TeamId)]
+	return schoolFilter(team, f.query.School) &&
+		teamFilter(team, f.query.TeamId) &&
+		languageFilter(run, f.query.Language) &&
+		statusFilter(run, f.status)
+}
+
+// convert 将提交转换为返回的格式
+func (f *runFilter) convert(run model.Run) *Run {
+	team := f.teams[string(run.TeamId)]
+	return &Run{
+		Id:           run.SubmissionId,
+		TeamId:       string(run.TeamId),
+		ProblemId:    fmt.Sprintf("%c", run.ProblemId+65), // 转换为 A, B, C, D, E, F, G, H, I, J
+		Team:         string(team.Name),
+		Organization: string(team.Organization),
+		Girl:         bool(team.Girl),
+		Unofficial:   team.IsUnofficial(),
+		Language:     run.Language,
+		Status:       string(run.Status),
+		Timestamp:    run.Timestamp,
+		Rejudged:     run.Rejudged(),
+		History:      run.History,
+	}
+}
+
 func schoolFilter(team model.Team, school string) bool {
 	if school == "" {
 		return true
@@ -181,10 +268,32 @@ func languageFilter(run model.Run, language string) bool {
 	return run.Language == language
 }
 
-func statusFilter(run model.Run, status string) bool {
+func statusFilter(run model.Run, status model.Verdict) bool {
 	if status == "" {
 		return true
 	}
 
 	return run.Status == status
 }
+
+// runAt 返回提交在时刻 t 的状态，评测历史只保留 t 之前的评测，没有被重测过时为空
+func runAt(run model.Run, t int) model.Run {
+	if len(run.History) == 0 {
+		return run
+	}
+
+	history := make([]model.Judgement, 0, len(run.History))
+	for i, judgement := range run.History {
+		if i > 0 && judgement.Timestamp > t {
+			break
+		}
+		history = append(history, judgement)
+	}
+
+	run.Status = run.StatusAt(t)
+	run.History = history
+	if !run.Rejudged() {
+		run.History = nil
+	}
+	return run
+}
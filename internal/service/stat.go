@@ -1,23 +1,40 @@
 package service
 
 import (
-	"sort"
-
 	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
 )
 
 const (
 	StatusAccepted = "accepted"
 	StatusRejected = "rejected"
+	StatusFrozen   = "frozen"
+	StatusPending  = "pending"
 
 	// 热力图时间段数量
-	TimeSlotCount = 50 // 将比赛时间划分为50个时间段
+	TimeSlotCount    = 50   // 默认将统计时间划分为50个时间段
+	MaxTimeSlotCount = 1000 // 时间段数量的上限，超过时增大时间段长度
 )
 
+// StatQuery 统计查询参数
+type StatQuery struct {
+	Group       string `form:"group"`        // 队伍组别
+	Time        *int   `form:"t"`            // 截止的相对时间(毫秒)，默认为比赛时长
+	Unfrozen    bool   `form:"unfrozen"`     // 是否查看封榜后的真实结果
+	BucketSize  int    `form:"bucket_size"`  // 热力图时间段长度(毫秒)，优先于时间段数量
+	BucketCount int    `form:"bucket_count"` // 热力图时间段数量，默认为 50
+}
+
+// Bucket 热力图的时间段 [Start, End)
+type Bucket struct {
+	Start int `json:"start"` // 开始的相对时间(毫秒)
+	End   int `json:"end"`   // 结束的相对时间(毫秒)，不包含
+}
+
 // HeatmapItem 表示一个时间点的提交情况
 type HeatmapItem struct {
 	Timestamp int    `json:"timestamp"` // 时间戳（相对时间，毫秒）
-	Status    string `json:"status"`    // 提交状态：accepted/rejected
+	Status    string `json:"status"`    // 提交状态：accepted/rejected/frozen/pending
 	Count     int    `json:"count"`     // 提交次数
 }
 
@@ -29,12 +46,16 @@ type ProblemHeatmap struct {
 
 // ContestHeatmap 表示整个比赛的提交热力图
 type ContestHeatmap struct {
-	Total    ProblemHeatmap   `json:"total"`    // 总体提交热力图
-	Problems []ProblemHeatmap `json:"problems"` // 每个题目的提交热力图
+	BucketSize int              `json:"bucket_size"` // 时间段长度(毫秒)
+	Buckets    []Bucket         `json:"buckets"`     // 时间段，最后一个时间段包含截止时刻
+	Total      ProblemHeatmap   `json:"total"`       // 总体提交热力图
+	Problems   []ProblemHeatmap `json:"problems"`    // 每个题目的提交热力图
 }
 
 // ContestStat 比赛统计数据
 type ContestStat struct {
+	// 统计截止的相对时间(毫秒)
+	Time int `json:"time"`
 	// 题目数量
 	ProblemCount int `json:"problem_count"`
 	// 队伍数量
@@ -45,6 +66,10 @@ type ContestStat struct {
 	AcceptedCount int `json:"accepted_count"`
 	// 不通过数量
 	RejectedCount int `json:"rejected_count"`
+	// 封榜后未公布结果的数量
+	FrozenCount int `json:"frozen_count"`
+	// 评测中的数量
+	PendingCount int `json:"pending_count"`
 	// 通过率
 	AcceptedRate float64 `json:"accepted_rate"`
 	// 比赛提交热力图
@@ -52,28 +77,18 @@ type ContestStat struct {
 }
 
 // GetContestStat 返回比赛统计数据
-func GetContestStat(path string, group string, t int) (result *ContestStat, err error) {
+func GetContestStat(path string, query StatQuery) (result *ContestStat, err error) {
+	group := query.Group
+	if query.BucketSize < 0 || query.BucketCount < 0 {
+		return nil, errors.NewBadRequest("时间段长度和数量不能为负数")
+	}
+
 	// 加载比赛配置
 	config, err := loadConfig(path)
 	if err != nil {
 		return nil, err
 	}
-
-	// 初始化统计数据
-	result = &ContestStat{
-		ProblemCount: config.ProblemQuantity,
-		ContestHeatmap: ContestHeatmap{
-			Problems: make([]ProblemHeatmap, config.ProblemQuantity),
-		},
-	}
-
-	// 初始化每个题目的热力图
-	for i := 0; i < config.ProblemQuantity; i++ {
-		result.ContestHeatmap.Problems[i] = ProblemHeatmap{
-			ProblemID:   string(rune('A' + i)),
-			Submissions: make([]HeatmapItem, 0),
-		}
-	}
+	hiddenAt := frozenAt(config, query.Unfrozen)
 
 	// 加载队伍列表
 	teamList, err := loadTeam(path)
@@ -83,7 +98,6 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 	teams := make(map[string]model.Team)
 	for _, team := range teamList {
 		if groupFilter(team, group) {
-			result.TeamCount++
 			teams[string(team.TeamId)] = team
 		}
 	}
@@ -93,14 +107,32 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 	if err != nil {
 		return nil, err
 	}
+	t := statTime(config, runList, query.Time)
+
+	// 划分时间段
+	bucketSize, buckets := statBuckets(t, query.BucketSize, query.BucketCount)
 
-	// 计算时间段长度
-	timeSlotDuration := t / TimeSlotCount // 每个时间段的长度（毫秒）
-	if timeSlotDuration < 1 {
-		timeSlotDuration = 1 // 确保至少为1毫秒
+	// 初始化统计数据
+	result = &ContestStat{
+		Time:         t,
+		ProblemCount: config.ProblemQuantity,
+		TeamCount:    len(teams),
+		ContestHeatmap: ContestHeatmap{
+			BucketSize: bucketSize,
+			Buckets:    buckets,
+			Problems:   make([]ProblemHeatmap, config.ProblemQuantity),
+		},
+	}
+
+	// 初始化每个题目的热力图
+	for i := 0; i < config.ProblemQuantity; i++ {
+		result.ContestHeatmap.Problems[i] = ProblemHeatmap{
+			ProblemID:   config.ProblemLabel(i),
+			Submissions: make([]HeatmapItem, 0),
+		}
 	}
 
-	// 创建时间段到提交次数的映射
+	// 创建时间段下标到提交次数的映射
 	totalSubmissions := make(map[int]map[string]int)
 	problemSubmissions := make([]map[int]map[string]int, config.ProblemQuantity)
 
@@ -111,11 +143,16 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 
 	// 统计提交数据
 	for _, run := range runList {
-		// 检查提交时间是否在比赛时间内
+		// 检查提交时间是否在统计时间内
 		if run.Timestamp > t {
 			continue
 		}
 
+		// 检查题目是否存在
+		if run.ProblemId < 0 || run.ProblemId >= config.ProblemQuantity {
+			continue
+		}
+
 		// 检查队伍是否在队伍列表中
 		if _, ok := teams[string(run.TeamId)]; !ok {
 			continue
@@ -123,8 +160,8 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 
 		result.RunCount++
 
-		// 计算时间段
-		timeSlot := (run.Timestamp / timeSlotDuration) * timeSlotDuration
+		// 计算时间段，截止时刻的提交计入最后一个时间段
+		timeSlot := min(max(run.Timestamp/bucketSize, 0), len(buckets)-1)
 
 		// 更新总体提交统计
 		if totalSubmissions[timeSlot] == nil {
@@ -132,7 +169,13 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 		}
 
 		status := StatusRejected
-		if run.Status == "ACCEPTED" {
+		if hiddenAt >= 0 && run.Timestamp >= hiddenAt {
+			status = StatusFrozen
+			result.FrozenCount++
+		} else if run.StatusAt(visibleTime(t, hiddenAt)).IsPending() {
+			status = StatusPending
+			result.PendingCount++
+		} else if run.StatusAt(visibleTime(t, hiddenAt)).IsAccepted() {
 			status = StatusAccepted
 			result.AcceptedCount++
 		} else {
@@ -147,72 +190,24 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 		problemSubmissions[run.ProblemId][timeSlot][status]++
 	}
 
-	// 生成总体热力图数据
-	totalHeatmap := make([]HeatmapItem, 0)
-	for slot := 0; slot < TimeSlotCount; slot++ {
-		timeSlot := slot * timeSlotDuration
-		acceptedCount := 0
-		rejectedCount := 0
-
-		if counts, ok := totalSubmissions[timeSlot]; ok {
-			acceptedCount = counts[StatusAccepted]
-			rejectedCount = counts[StatusRejected]
-		}
-
-		totalHeatmap = append(totalHeatmap, HeatmapItem{
-			Timestamp: timeSlot,
-			Status:    StatusAccepted,
-			Count:     acceptedCount,
-		})
-		totalHeatmap = append(totalHeatmap, HeatmapItem{
-			Timestamp: timeSlot,
-			Status:    StatusRejected,
-			Count:     rejectedCount,
-		})
+	// 热力图中的提交状态
+	statuses := []string{StatusAccepted, StatusRejected}
+	if hiddenAt >= 0 {
+		statuses = append(statuses, StatusFrozen)
+	}
+	if result.PendingCount > 0 {
+		statuses = append(statuses, StatusPending)
 	}
 
-	// 按时间戳排序
-	sort.Slice(totalHeatmap, func(i, j int) bool {
-		return totalHeatmap[i].Timestamp < totalHeatmap[j].Timestamp
-	})
-
+	// 生成总体热力图数据
 	result.ContestHeatmap.Total = ProblemHeatmap{
 		ProblemID:   "total",
-		Submissions: totalHeatmap,
+		Submissions: heatmapItems(totalSubmissions, buckets, statuses),
 	}
 
 	// 生成每个题目的热力图数据
-	for problemId := 0; problemId < config.ProblemQuantity; problemId++ {
-		submissions := make([]HeatmapItem, 0)
-
-		for slot := 0; slot < TimeSlotCount; slot++ {
-			timeSlot := slot * timeSlotDuration
-			acceptedCount := 0
-			rejectedCount := 0
-
-			if counts, ok := problemSubmissions[problemId][timeSlot]; ok {
-				acceptedCount = counts[StatusAccepted]
-				rejectedCount = counts[StatusRejected]
-			}
-
-			submissions = append(submissions, HeatmapItem{
-				Timestamp: timeSlot,
-				Status:    StatusAccepted,
-				Count:     acceptedCount,
-			})
-			submissions = append(submissions, HeatmapItem{
-				Timestamp: timeSlot,
-				Status:    StatusRejected,
-				Count:     rejectedCount,
-			})
-		}
-
-		// 按时间戳排序
-		sort.Slice(submissions, func(i, j int) bool {
-			return submissions[i].Timestamp < submissions[j].Timestamp
-		})
-
-		result.ContestHeatmap.Problems[problemId].Submissions = submissions
+	for i := 0; i < config.ProblemQuantity; i++ {
+		result.ContestHeatmap.Problems[i].Submissions = heatmapItems(problemSubmissions[i], buckets, statuses)
 	}
 
 	// 计算通过率
@@ -222,3 +217,58 @@ func GetContestStat(path string, group string, t int) (result *ContestStat, err
 
 	return result, nil
 }
+
+// heatmapItems 根据各时间段的提交统计生成热力图数据，每个时间段包含 statuses 中的每种状态
+func heatmapItems(submissions map[int]map[string]int, buckets []Bucket, statuses []string) []HeatmapItem {
+	items := make([]HeatmapItem, 0, len(buckets)*len(statuses))
+	for slot, bucket := range buckets {
+		for _, status := range statuses {
+			items = append(items, HeatmapItem{
+				Timestamp: bucket.Start,
+				Status:    status,
+				Count:     submissions[slot][status],
+			})
+		}
+	}
+
+	return items
+}
+
+// statTime 返回统计截止的相对时间(毫秒)
+//
+// 默认为比赛时长，未配置比赛时间时为最后一个提交的时间
+func statTime(config *model.ContestConfig, runList model.RunList, t *int) int {
+	if t != nil {
+		return *t
+	}
+	if duration := config.Duration(); duration > 0 {
+		return duration
+	}
+
+	last := 0
+	for _, run := range runList {
+		last = max(last, run.Timestamp)
+	}
+	return last
+}
+
+// statBuckets 将 [0, t] 划分为时间段，返回时间段长度和所有时间段
+//
+// size 大于 0 时按长度划分，否则按数量划分，数量默认为 TimeSlotCount。
+// 时间段长度至少为 1 毫秒，数量不超过 MaxTimeSlotCount，t 为 0 时只有一个时间段
+func statBuckets(t int, size int, count int) (int, []Bucket) {
+	span := max(t, 1)
+	if size <= 0 {
+		if count <= 0 {
+			count = TimeSlotCount
+		}
+		size = (span + count - 1) / count
+	}
+	size = max(size, (span+MaxTimeSlotCount-1)/MaxTimeSlotCount, 1)
+
+	buckets := make([]Bucket, 0, (span+size-1)/size)
+	for start := 0; start < span; start += size {
+		buckets = append(buckets, Bucket{Start: start, End: start + size})
+	}
+	return size, buckets
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	stderrors "errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/lllllan02/scoreboardv2/pkg/files"
+)
+
+// writeContest 在临时数据目录中写入比赛数据，返回比赛路径
+func writeContest(t *testing.T, config model.ContestConfig, teams model.TeamList, runs model.RunList) string {
+	t.Helper()
+
+	dataPath = t.TempDir()
+	path := "/contest"
+	for name, data := range map[string]any{"config.json": config, "team.json": teams, "run.json": runs} {
+		if err := files.Save(filepath.Join(dataPath, path, name), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestStatBuckets(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        int
+		size     int
+		count    int
+		wantSize int
+		wantLen  int
+	}{
+		{name: "zero time", t: 0, wantSize: 1, wantLen: 1},
+		{name: "default count", t: 100, wantSize: 2, wantLen: TimeSlotCount},
+		{name: "count", t: 100, count: 4, wantSize: 25, wantLen: 4},
+		{name: "size over count", t: 100, size: 30, count: 4, wantSize: 30, wantLen: 4},
+		{name: "size capped", t: 1000000, size: 1, wantSize: 1000000 / MaxTimeSlotCount, wantLen: MaxTimeSlotCount},
+		{name: "count capped", t: 1000000, count: 5000, wantSize: 1000000 / MaxTimeSlotCount, wantLen: MaxTimeSlotCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, buckets := statBuckets(tt.t, tt.size, tt.count)
+			if size != tt.wantSize || len(buckets) != tt.wantLen {
+				t.Fatalf("statBuckets() = %d, %d buckets, want %d, %d buckets", size, len(buckets), tt.wantSize, tt.wantLen)
+			}
+			if buckets[0].Start != 0 || buckets[len(buckets)-1].End < tt.t {
+				t.Errorf("buckets %v do not cover [0, %d]", buckets, tt.t)
+			}
+		})
+	}
+}
+
+func TestStatTime(t *testing.T) {
+	runs := model.RunList{{Timestamp: 1000}, {Timestamp: 5000}}
+	explicit := 0
+
+	tests := []struct {
+		name   string
+		config model.ContestConfig
+		runs   model.RunList
+		t      *int
+		want   int
+	}{
+		{name: "duration", config: model.ContestConfig{StartTime: 100, EndTime: 100 + 3600}, runs: runs, want: 3600000},
+		{name: "last run", runs: runs, want: 5000},
+		{name: "empty", want: 0},
+		{name: "explicit zero", config: model.ContestConfig{StartTime: 100, EndTime: 100 + 3600}, runs: runs, t: &explicit, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statTime(&tt.config, tt.runs, tt.t); got != tt.want {
+				t.Errorf("statTime() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetContestStat(t *testing.T) {
+	teams := model.TeamList{"1": {TeamId: "1", Name: "One", Official: true}}
+	timed := model.ContestConfig{StartTime: 1000, EndTime: 1000 + 3600, ProblemQuantity: 2}
+	untimed := model.ContestConfig{ProblemQuantity: 2}
+	runs := model.RunList{
+		{Status: model.VerdictAccepted, TeamId: "1", ProblemId: 0, Timestamp: 60000, SubmissionId: "1"},
+		{Status: model.VerdictWrongAnswer, TeamId: "1", ProblemId: 1, Timestamp: 1800000, SubmissionId: "2"},
+		{Status: model.VerdictAccepted, TeamId: "1", ProblemId: 1, Timestamp: 3600000, SubmissionId: "3"},
+		{Status: model.VerdictAccepted, TeamId: "1", ProblemId: 0, Timestamp: 4000000, SubmissionId: "4"},
+	}
+
+	tests := []struct {
+		name         string
+		config       model.ContestConfig
+		runs         model.RunList
+		query        StatQuery
+		wantTime     int
+		wantRuns     int
+		wantAccepted int
+		wantBuckets  int
+	}{
+		{
+			// 比赛结束后的提交不计入，结束时刻的提交计入最后一个时间段
+			name:         "overtime",
+			config:       timed,
+			runs:         runs,
+			wantTime:     3600000,
+			wantRuns:     3,
+			wantAccepted: 2,
+			wantBuckets:  TimeSlotCount,
+		},
+		{
+			name:        "empty",
+			config:      untimed,
+			runs:        model.RunList{},
+			wantTime:    0,
+			wantBuckets: 1,
+		},
+		{
+			// 未配置比赛时间时统计到最后一个提交
+			name:         "no duration",
+			config:       untimed,
+			runs:         runs,
+			wantTime:     4000000,
+			wantRuns:     4,
+			wantAccepted: 3,
+			wantBuckets:  TimeSlotCount,
+		},
+		{
+			name:         "bucket size",
+			config:       timed,
+			runs:         runs,
+			query:        StatQuery{BucketSize: 600000, BucketCount: 3},
+			wantTime:     3600000,
+			wantRuns:     3,
+			wantAccepted: 2,
+			wantBuckets:  6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeContest(t, tt.config, teams, tt.runs)
+
+			stat, err := GetContestStat(path, tt.query)
+			if err != nil {
+				t.Fatalf("GetContestStat() error = %v", err)
+			}
+			if stat.Time != tt.wantTime || stat.RunCount != tt.wantRuns || stat.AcceptedCount != tt.wantAccepted {
+				t.Errorf("GetContestStat() time = %d, runs = %d, accepted = %d, want %d, %d, %d",
+					stat.Time, stat.RunCount, stat.AcceptedCount, tt.wantTime, tt.wantRuns, tt.wantAccepted)
+			}
+			if got := len(stat.ContestHeatmap.Buckets); got != tt.wantBuckets {
+				t.Errorf("len(Buckets) = %d, want %d", got, tt.wantBuckets)
+			}
+
+			// 每个计入的提交都落在某个时间段中
+			total := 0
+			for _, item := range stat.ContestHeatmap.Total.Submissions {
+				total += item.Count
+			}
+			if total != tt.wantRuns {
+				t.Errorf("heatmap total = %d, want %d", total, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestGetContestStatLastBucket(t *testing.T) {
+	config := model.ContestConfig{StartTime: 1000, EndTime: 1000 + 3600, ProblemQuantity: 1}
+	teams := model.TeamList{"1": {TeamId: "1", Name: "One", Official: true}}
+	runs := model.RunList{{Status: model.VerdictAccepted, TeamId: "1", Timestamp: 3600000, SubmissionId: "1"}}
+	path := writeContest(t, config, teams, runs)
+
+	stat, err := GetContestStat(path, StatQuery{BucketCount: 4})
+	if err != nil {
+		t.Fatalf("GetContestStat() error = %v", err)
+	}
+
+	want := []HeatmapItem{
+		{Timestamp: 0, Status: StatusAccepted}, {Timestamp: 0, Status: StatusRejected},
+		{Timestamp: 900000, Status: StatusAccepted}, {Timestamp: 900000, Status: StatusRejected},
+		{Timestamp: 1800000, Status: StatusAccepted}, {Timestamp: 1800000, Status: StatusRejected},
+		{Timestamp: 2700000, Status: StatusAccepted, Count: 1}, {Timestamp: 2700000, Status: StatusRejected},
+	}
+	if !reflect.DeepEqual(stat.ContestHeatmap.Total.Submissions, want) {
+		t.Errorf("Total = %+v, want %+v", stat.ContestHeatmap.Total.Submissions, want)
+	}
+}
+
+func TestGetContestStatInvalidBuckets(t *testing.T) {
+	tests := []StatQuery{{BucketSize: -1}, {BucketCount: -1}}
+
+	for _, query := range tests {
+		_, err := GetContestStat("/contest", query)
+
+		var serviceErr *errors.ServiceError
+		if !stderrors.As(err, &serviceErr) || serviceErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("GetContestStat(%+v) error = %v, want 400", query, err)
+		}
+	}
+}
@@ -1,101 +1,216 @@
 package service
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lllllan02/scoreboardv2/internal/model"
+	"github.com/lllllan02/scoreboardv2/pkg/errors"
+	"github.com/lllllan02/scoreboardv2/pkg/fenwick"
+	"github.com/lllllan02/scoreboardv2/pkg/slices"
+)
+
+// TrendQuery 排名趋势查询参数
+type TrendQuery struct {
+	TeamIds  []string `form:"team_id"`  // 队伍 id，可以重复或用逗号分隔指定多个队伍
+	Group    string   `form:"group"`    // 队伍组别，只在该组别的队伍中计算排名
+	Time     *int     `form:"t"`        // 截止的相对时间(毫秒)，默认为比赛结束
+	Unfrozen bool     `form:"unfrozen"` // 是否查看封榜后的真实结果
+}
+
+// TeamTrend 队伍的排名趋势
 type TeamTrend struct {
-	Place int `json:"place"` // 排名
-	Time  int `json:"time"`  // 相对时间(ms)
+	TeamId string        `json:"team_id"` // 队伍 id
+	Team   string        `json:"team"`    // 队伍名称
+	Points []*TrendPoint `json:"points"`  // 排名、解题数或罚时发生变化的时刻
+}
+
+// TrendPoint 排名趋势中的一个点
+type TrendPoint struct {
+	Time    int `json:"time"`    // 相对时间(ms)
+	Place   int `json:"place"`   // 排名
+	Solved  int `json:"solved"`  // 解决题目数
+	Penalty int `json:"penalty"` // 罚时
+}
+
+// trendScore 决定排名的成绩，罚时为展示的罚时
+type trendScore struct {
+	solved  int
+	penalty int
+}
+
+// better 判断成绩 s 是否排在 o 前面
+func (s trendScore) better(o trendScore) bool {
+	if s.solved != o.solved {
+		return s.solved > o.solved
+	}
+	return s.penalty < o.penalty
+}
+
+// trendUpdate 某一时刻队伍成绩的变化
+type trendUpdate struct {
+	time   int
+	teamId string
+	score  trendScore
 }
 
-func GetTeamTrend(path string, teamId string) ([]*TeamTrend, error) {
+// GetTeamTrend 返回多个队伍随时间变化的排名
+//
+// 排名规则与榜单一致。按时间顺序处理所有事件，用树状数组维护每种成绩的队伍数量，
+// 队伍的排名为成绩更好的队伍数量加一
+func GetTeamTrend(path string, query TrendQuery) ([]*TeamTrend, error) {
+	// 获取比赛配置
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	rule := newPenaltyRule(config)
+
 	// 获取队伍信息
 	teamList, err := loadTeam(path)
 	if err != nil {
 		return nil, err
 	}
+	teams := make(map[string]model.Team, len(teamList))
+	for _, team := range teamList {
+		teams[string(team.TeamId)] = team
+	}
 
-	// 获取提交记录
-	runList, err := loadRun(path)
+	// 获取排名引擎
+	engine, err := loadRankEngine(path, query.Unfrozen)
 	if err != nil {
 		return nil, err
 	}
 
-	// 初始化所有队伍的状态
-	type TeamState struct {
-		solved  int // 解决题目数
-		penalty int // 罚时
+	// 需要返回趋势的队伍
+	teamIds := trendTeamIds(query.TeamIds)
+	if len(teamIds) == 0 {
+		return nil, errors.NewBadRequest("缺少队伍 id")
 	}
-	teamStates := make(map[string]*TeamState)
-	problemStates := make(map[string]map[int]bool) // teamId -> problemId -> solved
-
-	// 初始化每个队伍的状态
-	for id := range teamList {
-		teamStates[string(id)] = &TeamState{}
-		problemStates[string(id)] = make(map[int]bool)
+	for _, teamId := range teamIds {
+		team, ok := teams[teamId]
+		if !ok {
+			return nil, errors.NewNotFound(fmt.Sprintf("队伍 %s 不存在", teamId))
+		}
+		if !groupFilter(team, query.Group) {
+			return nil, errors.NewBadRequest(fmt.Sprintf("队伍 %s 不属于组别 %s", teamId, query.Group))
+		}
 	}
 
-	// 用于存储排名变化的结果
-	var trends []*TeamTrend
-	// 添加初始排名
-	trends = append(trends, &TeamTrend{
-		Place: 1,
-		Time:  0,
-	})
-
-	// 遍历每个提交记录
-	for _, run := range runList {
-		curTeamId := string(run.TeamId)
-		problemId := run.ProblemId
+	t := contestEnd(config)
+	if query.Time != nil {
+		t = *query.Time
+	}
 
-		// 如果题目已经解决，跳过
-		if problemStates[curTeamId][problemId] {
-			continue
+	// 按时间处理事件，记录参与排名的队伍的成绩变化
+	var updates []trendUpdate
+	state := make(boardState)
+	for index, ev := range engine.events {
+		if ev.time > t {
+			break
 		}
+		engine.apply(state, index)
 
-		// 如果是编译错误，跳过
-		if run.Status == "COMPILATION_ERROR" {
+		teamId := string(engine.runs[ev.run].TeamId)
+		cells, ok := state[teamId]
+		if !ok || !groupFilter(teams[teamId], query.Group) {
 			continue
 		}
 
-		state, ok := teamStates[curTeamId]
-		if !ok {
-			continue
+		score := trendScore{}
+		penalty := 0
+		for _, c := range cells {
+			if c.Solved {
+				score.solved++
+				penalty += c.penalty
+			}
 		}
+		score.penalty = rule.display(penalty)
+		updates = append(updates, trendUpdate{time: ev.time, teamId: teamId, score: score})
+	}
 
-		if run.Status == "ACCEPTED" {
-			// 更新队伍状态
-			state.solved++
-			state.penalty += run.Timestamp / 1000 / 60 // 转换为分钟
-			problemStates[curTeamId][problemId] = true
-		} else {
-			// 错误提交加罚时
-			state.penalty += 20
+	// 将所有出现过的成绩从好到差编号
+	scores := []trendScore{{}}
+	for _, update := range updates {
+		scores = append(scores, update.score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].better(scores[j])
+	})
+	order := make(map[trendScore]int, len(scores))
+	for _, score := range scores {
+		if _, ok := order[score]; !ok {
+			order[score] = len(order)
 		}
+	}
 
-		// 计算当前队伍的排名
-		place := 1
-		targetState, ok := teamStates[teamId]
-		if !ok {
-			continue
+	// 初始时所有参与排名的队伍都没有成绩
+	tree := fenwick.New(len(order))
+	current := make(map[string]trendScore)
+	for teamId, team := range teams {
+		if groupFilter(team, query.Group) {
+			tree.Add(order[trendScore{}], 1)
+			current[teamId] = trendScore{}
 		}
+	}
 
-		for tid, s := range teamStates {
-			if tid == teamId {
-				continue
-			}
-			// 排名规则：解题数量多的排前面，罚时少的排前面
-			if s.solved > targetState.solved ||
-				(s.solved == targetState.solved && s.penalty < targetState.penalty) {
-				place++
-			}
+	trends := make([]*TeamTrend, 0, len(teamIds))
+	for _, teamId := range teamIds {
+		trends = append(trends, &TeamTrend{
+			TeamId: teamId,
+			Team:   string(teams[teamId].Name),
+			Points: []*TrendPoint{{Place: tree.Sum(order[trendScore{}]) + 1}},
+		})
+	}
+
+	for i, update := range updates {
+		if score, ok := current[update.teamId]; ok {
+			tree.Add(order[score], -1)
 		}
+		tree.Add(order[update.score], 1)
+		current[update.teamId] = update.score
 
-		// 如果排名发生变化，记录新的趋势点
-		if len(trends) == 0 || trends[len(trends)-1].Place != place {
-			trends = append(trends, &TeamTrend{
-				Place: place,
-				Time:  run.Timestamp,
+		// 同一时刻的事件全部处理后再记录排名
+		if i+1 < len(updates) && updates[i+1].time == update.time {
+			continue
+		}
+		for _, trend := range trends {
+			score := current[trend.TeamId]
+			trend.addPoint(&TrendPoint{
+				Time:    update.time,
+				Place:   tree.Sum(order[score]) + 1,
+				Solved:  score.solved,
+				Penalty: score.penalty,
 			})
 		}
 	}
 
 	return trends, nil
 }
+
+// addPoint 添加趋势点，排名、解题数和罚时都没有变化时忽略
+func (t *TeamTrend) addPoint(point *TrendPoint) {
+	last := t.Points[len(t.Points)-1]
+	if last.Place == point.Place && last.Solved == point.Solved && last.Penalty == point.Penalty {
+		return
+	}
+
+	// 同一时刻只保留最后的状态
+	if last.Time == point.Time {
+		*last = *point
+		return
+	}
+	t.Points = append(t.Points, point)
+}
+
+// trendTeamIds 解析查询的队伍 id，支持逗号分隔
+func trendTeamIds(values []string) []string {
+	var teamIds []string
+	for _, value := range values {
+		for _, teamId := range strings.Split(value, ",") {
+			teamIds = append(teamIds, strings.TrimSpace(teamId))
+		}
+	}
+	return slices.Unique(slices.RemoveEmpty(teamIds))
+}
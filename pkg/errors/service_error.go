@@ -60,6 +60,14 @@ func NewBadRequest(message string) *ServiceError {
 	}
 }
 
+// NewUnauthorized 创建一个401错误
+func NewUnauthorized(message string) *ServiceError {
+	return &ServiceError{
+		StatusCode: http.StatusUnauthorized,
+		Message:    message,
+	}
+}
+
 // NewInternalError 创建一个500错误
 func NewInternalError(message string, err error) *ServiceError {
 	return &ServiceError{
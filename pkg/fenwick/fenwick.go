@@ -0,0 +1,36 @@
+package fenwick
+
+// Tree 树状数组，支持单点修改和前缀求和，均为 O(log n)
+type Tree struct {
+	tree []int // tree[i] 保存 (i - lowbit(i), i] 区间的和，下标从 1 开始
+}
+
+// New 创建一个长度为 n 的树状数组，初始值均为 0
+func New(n int) *Tree {
+	return &Tree{tree: make([]int, n+1)}
+}
+
+// Len 返回长度
+func (t *Tree) Len() int {
+	return len(t.tree) - 1
+}
+
+// Add 将下标 i(从 0 开始) 的值加上 delta
+func (t *Tree) Add(i int, delta int) {
+	for i++; i < len(t.tree); i += i & -i {
+		t.tree[i] += delta
+	}
+}
+
+// Sum 返回下标 [0, i) 的前缀和
+func (t *Tree) Sum(i int) int {
+	if i > t.Len() {
+		i = t.Len()
+	}
+
+	sum := 0
+	for ; i > 0; i -= i & -i {
+		sum += t.tree[i]
+	}
+	return sum
+}
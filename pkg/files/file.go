@@ -16,11 +16,14 @@ func Load[T any](path string, target T) error {
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	return json.NewDecoder(file).Decode(target)
 }
 
 // Save 保存数据到本地
+//
+// 先写入同目录下的临时文件再重命名，保证读取方不会读到写了一半的文件
 func Save(path string, data any) error {
 	// 格式化JSON为美观缩进格式
 	prettyJSON, err := json.MarshalIndent(data, "", "  ")
@@ -29,14 +32,33 @@ func Save(path string, data any) error {
 	}
 
 	// 创建目录
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	// 保存到本地
-	if err := os.WriteFile(path, prettyJSON, 0644); err != nil {
+	// 写入临时文件
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(prettyJSON); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
 		return err
 	}
 
-	return nil
+	// 替换为正式文件
+	return os.Rename(file.Name(), path)
 }
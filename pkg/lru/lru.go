@@ -0,0 +1,92 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache 并发安全的 LRU 缓存
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int                 // 最大容量，小于等于 0 表示不限制
+	ll       *list.List          // 访问顺序，队首为最近访问
+	items    map[K]*list.Element // key -> 链表节点
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// New 创建一个新的 LRU 缓存
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	return &Cache[K, V]{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get 获取缓存值，并将其标记为最近访问
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, hit := c.items[key]; hit {
+		c.ll.MoveToFront(e)
+		return e.Value.(*entry[K, V]).value, true
+	}
+	return
+}
+
+// Add 添加缓存值，超出容量时淘汰最久未访问的值
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, hit := c.items[key]; hit {
+		c.ll.MoveToFront(e)
+		e.Value.(*entry[K, V]).value = value
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value})
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Remove 删除缓存值
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, hit := c.items[key]; hit {
+		c.removeElement(e)
+	}
+}
+
+// RemoveFunc 删除所有满足条件的缓存值
+func (c *Cache[K, V]) RemoveFunc(match func(key K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.items {
+		if match(key) {
+			c.removeElement(e)
+		}
+	}
+}
+
+// Len 返回缓存值数量
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *Cache[K, V]) removeElement(e *list.Element) {
+	c.ll.Remove(e)
+	delete(c.items, e.Value.(*entry[K, V]).key)
+}
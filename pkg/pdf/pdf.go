@@ -0,0 +1,320 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// 常用纸张尺寸(点，1/72 英寸)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// 中文字体使用阅读器内置的 STSong-Light，文件中不嵌入字体
+const (
+	fontName     = "STSong-Light"
+	fontEncoding = "UniGB-UCS2-H"
+)
+
+// Document PDF 文档，坐标原点在页面左上角，单位为点
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+	images []*Image
+}
+
+// Page 文档中的一页
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	images  map[*Image]bool // 页面使用的图片
+}
+
+// Image 文档中的图片，可以在多个页面中重复使用
+type Image struct {
+	id     int // 图片编号，从 1 开始
+	width  int
+	height int
+	rgb    []byte // RGB 像素
+	alpha  []byte // 透明度，图片不透明时为空
+}
+
+// New 创建一个页面大小为 width × height 的文档
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Width 返回页面宽度
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+// Height 返回页面高度
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// AddPage 添加新的一页
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d, images: make(map[*Image]bool)}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// AddImage 添加图片，透明的部分保留透明度
+func (d *Document) AddImage(img image.Image) *Image {
+	bounds := img.Bounds()
+	result := &Image{id: len(d.images) + 1, width: bounds.Dx(), height: bounds.Dy()}
+	result.rgb = make([]byte, 0, result.width*result.height*3)
+
+	alpha := make([]byte, 0, result.width*result.height)
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			result.rgb = append(result.rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xFF
+		}
+	}
+	if !opaque {
+		result.alpha = alpha
+	}
+
+	d.images = append(d.images, result)
+	return result
+}
+
+// Size 返回图片的像素大小
+func (img *Image) Size() (width, height int) {
+	return img.width, img.height
+}
+
+// TextWidth 返回文本在字号 size 下的宽度，ASCII 字符为半角，其余字符为全角
+func TextWidth(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r < 0x80 {
+			width += 0.5
+		} else {
+			width++
+		}
+	}
+	return width * size
+}
+
+// Truncate 截断文本使其宽度不超过 width，截断时以 … 结尾
+func Truncate(text string, size float64, width float64) string {
+	if TextWidth(text, size) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes) + "…"
+}
+
+// Rect 绘制左上角为 (x, y) 的矩形，fill 和 stroke 为空时不填充或不描边
+func (p *Page) Rect(x, y, width, height float64, fill, stroke color.Color) {
+	if fill == nil && stroke == nil {
+		return
+	}
+
+	p.printf("q ")
+	if fill != nil {
+		p.printf("%s rg ", rgb(fill))
+	}
+	if stroke != nil {
+		p.printf("%s RG 0.5 w ", rgb(stroke))
+	}
+	p.printf("%.2f %.2f %.2f %.2f re ", x, p.doc.height-y-height, width, height)
+	switch {
+	case fill != nil && stroke != nil:
+		p.printf("B Q\n")
+	case fill != nil:
+		p.printf("f Q\n")
+	default:
+		p.printf("S Q\n")
+	}
+}
+
+// Line 绘制从 (x1, y1) 到 (x2, y2) 的线段
+func (p *Page) Line(x1, y1, x2, y2 float64, stroke color.Color, width float64) {
+	p.printf("q %s RG %.2f w %.2f %.2f m %.2f %.2f l S Q\n",
+		rgb(stroke), width, x1, p.doc.height-y1, x2, p.doc.height-y2)
+}
+
+// Text 在 (x, y) 处绘制文本，y 为文本基线的位置
+func (p *Page) Text(x, y float64, size float64, fill color.Color, text string) {
+	p.printf("q %s rg BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET Q\n",
+		rgb(fill), size, x, p.doc.height-y, encodeText(text))
+}
+
+// Image 将图片绘制在左上角为 (x, y)、大小为 width × height 的区域
+func (p *Page) Image(img *Image, x, y, width, height float64) {
+	p.images[img] = true
+	p.printf("q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, x, p.doc.height-y-height, img.id)
+}
+
+// printf 向页面内容追加绘制指令
+func (p *Page) printf(format string, args ...any) {
+	fmt.Fprintf(&p.content, format, args...)
+}
+
+// rgb 返回颜色的 PDF 表示
+func rgb(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(r)/0xFFFF, float64(g)/0xFFFF, float64(b)/0xFFFF)
+}
+
+// encodeText 将文本编码为 UCS-2 大端序的十六进制字符串，不在基本平面的字符替换为 ?
+func encodeText(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if r > 0xFFFF {
+			r = '?'
+		}
+		fmt.Fprintf(&sb, "%04X", r)
+	}
+	return sb.String()
+}
+
+// WriteTo 将文档写入 w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pw := &writer{w: bufio.NewWriter(w)}
+	pw.printf("%%PDF-1.4\n%%\xE2\xE3\xCF\xD3\n")
+
+	// 对象编号：1 目录，2 页面树，3-5 字体，之后依次为图片和页面
+	const catalog, pages, font, cidFont, descriptor = 1, 2, 3, 4, 5
+	imageObjects := make(map[*Image]int)
+	next := 6
+	for _, img := range d.images {
+		imageObjects[img] = next
+		next++
+		if img.alpha != nil {
+			next++ // 透明度
+		}
+	}
+	pageObjects := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageObjects[i] = next
+		next += 2 // 页面和内容
+	}
+
+	pw.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	kids := make([]string, len(pageObjects))
+	for i, id := range pageObjects {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	pw.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+
+	// ASCII 字符使用半角宽度，与 TextWidth 一致
+	pw.object(font, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /%s /DescendantFonts [%d 0 R] >>",
+		fontName, fontEncoding, cidFont))
+	pw.object(cidFont, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> "+
+		"/FontDescriptor %d 0 R /DW 1000 /W [1 95 500 7712 7806 500] >>", fontName, descriptor))
+	pw.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 6 "+
+		"/FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>", fontName))
+
+	for _, img := range d.images {
+		id := imageObjects[img]
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8", img.width, img.height)
+		if img.alpha != nil {
+			dict += fmt.Sprintf(" /SMask %d 0 R", id+1)
+		}
+		pw.stream(id, dict, img.rgb)
+		if img.alpha != nil {
+			pw.stream(id+1, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8", img.width, img.height), img.alpha)
+		}
+	}
+
+	for i, page := range d.pages {
+		id := pageObjects[i]
+		var xobjects strings.Builder
+		for _, img := range d.images {
+			if page.images[img] {
+				fmt.Fprintf(&xobjects, " /Im%d %d 0 R", img.id, imageObjects[img])
+			}
+		}
+		pw.object(id, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 %d 0 R >> /XObject <<%s >> >> /Contents %d 0 R >>",
+			pages, d.width, d.height, font, xobjects.String(), id+1))
+		pw.stream(id+1, "", page.content.Bytes())
+	}
+
+	// 交叉引用表
+	xref := pw.n
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for i := 1; i <= len(pw.offsets); i++ {
+		pw.printf("%010d 00000 n \n", pw.offsets[i])
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, catalog, xref)
+
+	if pw.err == nil {
+		pw.err = pw.w.Flush()
+	}
+	return pw.n, pw.err
+}
+
+// writer 记录写入位置和对象偏移的输出，出错后忽略之后的写入
+type writer struct {
+	w       *bufio.Writer
+	n       int64
+	offsets map[int]int64 // 对象编号 -> 偏移
+	err     error
+}
+
+// printf 写入格式化的文本
+func (w *writer) printf(format string, args ...any) {
+	w.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+// write 写入数据
+func (w *writer) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(data)
+	w.n += int64(n)
+	w.err = err
+}
+
+// object 写入一个对象
+func (w *writer) object(id int, body string) {
+	if w.offsets == nil {
+		w.offsets = make(map[int]int64)
+	}
+	w.offsets[id] = w.n
+	w.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream 写入一个压缩的流对象，dict 为流字典中除长度和压缩方式外的内容
+func (w *writer) stream(id int, dict string, data []byte) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(data)
+	zw.Close()
+
+	if w.offsets == nil {
+		w.offsets = make(map[int]int64)
+	}
+	w.offsets[id] = w.n
+	w.printf("%d 0 obj\n<< %s /Length %d /Filter /FlateDecode >>\nstream\n", id, dict, compressed.Len())
+	w.write(compressed.Bytes())
+	w.printf("\nendstream\nendobj\n")
+}
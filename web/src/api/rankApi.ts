@@ -1,6 +1,6 @@
 import axios from "axios";
 import { Rank } from "../types/rank";
-import { TrendPoint } from "../types/trend";
+import { TeamTrend, TrendPoint } from "../types/trend";
 
 // 请求缓存
 const requestCache = new Map<string, { data: any; timestamp: number }>();
@@ -58,12 +58,13 @@ export const getContestRank = async (
   }
 };
 
-export const getTeamTrend = async (contestPath: string, teamId: string): Promise<TrendPoint[]> => {
+// 获取多个队伍的排名趋势
+export const getTeamTrends = async (contestPath: string, teamIds: string[]): Promise<TeamTrend[]> => {
   try {
-    console.log('请求趋势数据:', `/api/team-trend${contestPath}?team_id=${teamId}`);
-    const response = await axios.get(`/api/team-trend${contestPath}?team_id=${teamId}`);
-    console.log('趋势数据响应:', response);
-    
+    const params = new URLSearchParams();
+    teamIds.forEach((teamId) => params.append("team_id", teamId));
+    const response = await axios.get(`/api/team-trend${contestPath}?${params.toString()}`);
+
     // 检查响应数据结构
     if (response.data && response.data.data) {
       return response.data.data;
@@ -74,3 +75,9 @@ export const getTeamTrend = async (contestPath: string, teamId: string): Promise
     throw error;
   }
 };
+
+// 获取单个队伍的排名趋势
+export const getTeamTrend = async (contestPath: string, teamId: string): Promise<TrendPoint[]> => {
+  const trends = await getTeamTrends(contestPath, [teamId]);
+  return trends.find((trend) => trend.team_id === teamId)?.points ?? [];
+};
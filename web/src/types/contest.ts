@@ -18,6 +18,7 @@ export interface ContestConfig {
   organization?: string;
   status_time_display?: StatusTimeDisplay;
   medal?: Medal;
+  medal_counts?: Record<string, OfficialMedal>; // 组别 -> 按最终排名计算的奖牌数量
   balloon_color?: BalloonColor[];
   logo?: Logo;
   link?: Link;
@@ -1,8 +1,12 @@
 export interface TrendPoint {
-  place: number;  // 排名
-  time: number;   // 相对时间（毫秒）
+  time: number;    // 相对时间（毫秒）
+  place: number;   // 排名
+  solved: number;  // 解决题目数
+  penalty: number; // 罚时
 }
 
 export interface TeamTrend {
-  points: TrendPoint[];
-} 
\ No newline at end of file
+  team_id: string;      // 队伍 id
+  team: string;         // 队伍名称
+  points: TrendPoint[]; // 排名、解题数或罚时发生变化的时刻
+}